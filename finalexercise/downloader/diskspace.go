@@ -0,0 +1,66 @@
+package downloader
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// errNotSupported is returned by the platform helpers when free space
+// lookup or preallocation is not available on the current OS/filesystem.
+var errNotSupported = errors.New("not supported on this platform")
+
+// InsufficientSpaceError is returned before any data is transferred when the
+// destination filesystem does not have room for the whole file.
+type InsufficientSpaceError struct {
+	Path      string // Directory that was checked
+	Required  int64  // Bytes needed to hold the file
+	Available int64  // Bytes available to the current user
+}
+
+func (e *InsufficientSpaceError) Error() string {
+	return fmt.Sprintf("insufficient disk space in %s: need %d bytes, %d available", e.Path, e.Required, e.Available)
+}
+
+// checkDiskSpace verifies that the destination filesystem can hold the file.
+// Space already used by an existing destination file is counted as available,
+// since createEmptyFile truncates it.
+func (d *Downloader) checkDiskSpace() error {
+	dir := filepath.Dir(d.DestFile)
+	available, err := freeSpace(dir)
+	if err != nil {
+		if errors.Is(err, errNotSupported) {
+			log.Printf("Free space check not supported for %s, skipping preflight.", dir)
+			return nil
+		}
+		return fmt.Errorf("failed to query free space in %s: %w", dir, err)
+	}
+
+	required := d.fileSize
+	if stat, err := os.Stat(d.DestFile); err == nil && stat.Mode().IsRegular() {
+		available += allocatedSize(stat)
+	}
+
+	if required > available {
+		return &InsufficientSpaceError{Path: dir, Required: required, Available: available}
+	}
+	return nil
+}
+
+// allocateFile reserves disk blocks for the whole file when Preallocate is
+// set, falling back to a sparse Truncate when the filesystem can't do it.
+func (d *Downloader) allocateFile() error {
+	if d.Preallocate && d.fileSize > 0 {
+		err := preallocate(d.file, d.fileSize)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errNotSupported) {
+			return fmt.Errorf("failed to preallocate %d bytes: %w", d.fileSize, err)
+		}
+		log.Printf("Preallocation not supported for %s, falling back to sparse file.", d.DestFile)
+	}
+	return d.file.Truncate(d.fileSize)
+}
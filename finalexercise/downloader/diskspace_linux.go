@@ -0,0 +1,37 @@
+//go:build linux
+
+package downloader
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// freeSpace returns the number of bytes available to unprivileged users on
+// the filesystem holding dir.
+func freeSpace(dir string) (int64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return 0, err
+	}
+	return int64(st.Bavail) * int64(st.Bsize), nil
+}
+
+// allocatedSize returns the bytes actually allocated on disk for a file,
+// which is less than its size for sparse files.
+func allocatedSize(fi os.FileInfo) int64 {
+	if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+		return int64(st.Blocks) * 512
+	}
+	return fi.Size()
+}
+
+// preallocate reserves size bytes of disk blocks for f with fallocate(2).
+func preallocate(f *os.File, size int64) error {
+	err := syscall.Fallocate(int(f.Fd()), 0, 0, size)
+	if errors.Is(err, syscall.EOPNOTSUPP) || errors.Is(err, syscall.ENOSYS) {
+		return errNotSupported
+	}
+	return err
+}
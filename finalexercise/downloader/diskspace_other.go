@@ -0,0 +1,17 @@
+//go:build !linux
+
+package downloader
+
+import "os"
+
+func freeSpace(dir string) (int64, error) {
+	return 0, errNotSupported
+}
+
+func allocatedSize(fi os.FileInfo) int64 {
+	return fi.Size()
+}
+
+func preallocate(f *os.File, size int64) error {
+	return errNotSupported
+}
@@ -0,0 +1,71 @@
+package downloader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCheckDiskSpaceInsufficient(t *testing.T) {
+	destFile := filepath.Join(t.TempDir(), "huge.bin")
+	if runtime.GOOS != "linux" {
+		t.Skip("free space lookup only supported on linux")
+	}
+	d := NewDownloader("", destFile, 1, 1024, 0, time.Second)
+	d.fileSize = 1 << 62
+
+	err := d.checkDiskSpace()
+
+	var spaceErr *InsufficientSpaceError
+	if !errors.As(err, &spaceErr) {
+		t.Fatalf("Expected InsufficientSpaceError, got %v", err)
+	}
+	if spaceErr.Required != d.fileSize {
+		t.Errorf("Expected required %d, got %d", d.fileSize, spaceErr.Required)
+	}
+	if _, err := os.Stat(destFile); !os.IsNotExist(err) {
+		t.Errorf("Preflight must not create %s", destFile)
+	}
+}
+
+func TestCreateEmptyFilePreallocate(t *testing.T) {
+	destFile := filepath.Join(t.TempDir(), "prealloc.bin")
+	d := NewDownloader("", destFile, 1, 1024, 0, time.Second)
+	d.fileSize = 64 * 1024
+	d.Preallocate = true
+
+	if err := d.checkDiskSpace(); err != nil {
+		t.Fatalf("checkDiskSpace failed: %v", err)
+	}
+	if err := d.createEmptyFile(); err != nil {
+		t.Fatalf("createEmptyFile failed: %v", err)
+	}
+	defer d.file.Close()
+
+	stat, err := os.Stat(destFile)
+	if err != nil {
+		t.Fatalf("Failed to stat created file: %v", err)
+	}
+	if stat.Size() != d.fileSize {
+		t.Errorf("Expected file size %d, got %d", d.fileSize, stat.Size())
+	}
+
+	// A sparse Truncate also has the right size, so check the blocks too
+	if runtime.GOOS != "linux" {
+		return
+	}
+	probe, err := os.Create(filepath.Join(t.TempDir(), "probe.bin"))
+	if err != nil {
+		t.Fatalf("Failed to create probe file: %v", err)
+	}
+	defer probe.Close()
+	if err := preallocate(probe, 1); errors.Is(err, errNotSupported) {
+		t.Skip("filesystem doesn't support fallocate")
+	}
+	if allocatedSize(stat) < d.fileSize {
+		t.Errorf("Expected at least %d bytes allocated, got %d", d.fileSize, allocatedSize(stat))
+	}
+}
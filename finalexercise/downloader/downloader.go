@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/md5"
+	"crypto/tls"
 	"encoding/hex"
 	"errors"
 	"fmt"
@@ -27,6 +28,9 @@ type Downloader struct {
 	ChunkSize     int64
 	Retries       int
 	Timeout       time.Duration
+	Preallocate   bool    // Reserve disk blocks up front instead of creating a sparse file
+	ConnMode      string  // One of ConnAuto, ConnHTTP1 or ConnHTTP2
+	Window        *Window // Only transfer inside this daily window, nil for any time
 
 	ctx       context.Context
 	cancel    context.CancelFunc
@@ -34,10 +38,22 @@ type Downloader struct {
 	file      *os.File // File handle for writing
 	fileSize  int64
 	etag      string
+	modTime   time.Time  // Last-Modified of the file, zero if the server doesn't say
 	errChan   chan error // Channel to propagate errors from goroutines
-	mu        sync.Mutex // Mutex for file writing, though os.File.WriteAt is generally safe
+	mu        sync.Mutex // Guards the run statistics below; os.File.WriteAt is safe on its own
 	client    *http.Client
+	clients   []*http.Client // One client per goroutine slot in ConnHTTP2 mode
+	tlsConfig *tls.Config    // TLS settings for the transports, nil for defaults
+	proto     string         // Protocol negotiated on the HEAD request
 	startTime time.Time
+
+	// Run statistics, used by Report
+	numChunks    int
+	chunkRetries map[int]int // Chunk ID -> retries needed
+	verification string
+	elapsed      time.Duration
+	waited       time.Duration // Time spent outside the transfer window
+	runErr       error
 }
 
 // Chunk represents a segment of the file to be downloaded.
@@ -56,26 +72,53 @@ func NewDownloader(url, destFile string, numGoroutines int, chunkSize int64, ret
 		ChunkSize:     chunkSize,
 		Retries:       retries,
 		Timeout:       timeout,
+		ConnMode:      ConnAuto,
 		errChan:       make(chan error, numGoroutines), // Buffered to prevent blocking
 		client: &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: newTransport(max(numGoroutines, 1), timeout, nil, false),
 		},
 	}
 }
 
 // Run orchestrates the entire download process.
 func (d *Downloader) Run() error {
-	d.startTime = time.Now()
+	return d.run(nil)
+}
+
+// RunPlan is Run with the file metadata of p, returned by Plan, instead of
+// sending another HEAD request.
+func (d *Downloader) RunPlan(p *Plan) error {
+	return d.run(p)
+}
+
+func (d *Downloader) run(plan *Plan) (err error) {
+	d.resetStats()
+	defer func() { d.finishStats(err) }()
+
 	d.ctx, d.cancel = context.WithCancel(context.Background())
 	defer d.cancel() // Ensure cancel is called on exit
 
-	log.Printf("Getting metadata for %s...", d.URL)
-	if err := d.getMetadata(); err != nil {
-		d.cleanup() // Clean up file if metadata fetch fails
-		return fmt.Errorf("failed to get file metadata: %w", err)
+	if err := d.setupClients(); err != nil {
+		return err
+	}
+
+	if plan != nil {
+		d.fileSize, d.etag, d.modTime, d.proto = plan.FileSize, plan.ETag, plan.LastModified, plan.Protocol
+	} else {
+		log.Printf("Getting metadata for %s...", d.URL)
+		if err := d.getMetadata(); err != nil {
+			d.cleanup() // Clean up file if metadata fetch fails
+			return fmt.Errorf("failed to get file metadata: %w", err)
+		}
 	}
 
 	log.Printf("File size: %d bytes, ETag: %s", d.fileSize, d.etag)
+	d.logProtocol()
+
+	if err := d.checkDiskSpace(); err != nil {
+		return fmt.Errorf("disk space preflight failed: %w", err)
+	}
 
 	log.Printf("Creating empty file %s with size %d bytes...", d.DestFile, d.fileSize)
 	if err := d.createEmptyFile(); err != nil {
@@ -85,36 +128,27 @@ func (d *Downloader) Run() error {
 	defer d.file.Close() // Close the file when Run exits
 
 	chunks := d.calculateChunks()
+	d.numChunks = len(chunks)
 	log.Printf("Dividing into %d chunks. Starting parallel download with %d goroutines...", len(chunks), d.NumGoroutines)
 
-	// Start a goroutine to listen for errors and cancel if one occurs
-	go func() {
-		select {
-		case err := <-d.errChan:
-			log.Printf("Error received from a goroutine: %v. Cancelling all downloads...", err)
-			d.cancel() // Cancel all other goroutines
-		case <-d.ctx.Done():
-			// Context was cancelled, potentially from an external signal or cleanup
-		}
-	}()
-
-	// Semaphore to limit the number of concurrent goroutines
-	sem := make(chan struct{}, d.NumGoroutines)
+	// A failing chunk cancels d.ctx through reportError and leaves its error
+	// in errChan, where it is picked up after the downloads below.
 
-	for _, chunk := range chunks {
-		d.wg.Add(1)
-		sem <- struct{}{} // Acquire a token
-		go func(c Chunk) {
-			defer func() {
-				<-sem // Release the token
-				d.wg.Done()
-			}()
-			d.downloadChunk(c)
-		}(chunk)
+	// Download in transfer windows until no chunk is left. Without a
+	// window this is a single pass.
+	pending := chunks
+	for len(pending) > 0 {
+		if err := d.waitForWindow(); err != nil {
+			break // Cancelled while waiting, handled below
+		}
+		winCtx, stop := d.windowContext()
+		pending = d.downloadChunks(winCtx, pending)
+		stop()
+		if len(pending) > 0 && d.ctx.Err() == nil {
+			log.Printf("Transfer window %s closed, pausing %d unfinished chunks.", d.Window, len(pending))
+		}
 	}
 
-	d.wg.Wait() // Wait for all download goroutines to finish
-
 	// Check if the context was cancelled due to an error
 	select {
 	case <-d.ctx.Done():
@@ -140,24 +174,75 @@ func (d *Downloader) Run() error {
 		// No cancellation, proceed to verify
 	}
 
-	// Verify MD5 if ETag was provided (and assumed to be MD5)
-	if d.etag != "" {
+	// Verify MD5 if the ETag is an MD5 sum
+	if d.verificationMethod() == VerifyMD5 {
 		log.Println("Verifying file MD5 signature...")
 		if err := d.verifyMD5(); err != nil {
+			d.setVerification(VerificationFailed)
 			d.cleanup()
 			return fmt.Errorf("MD5 verification failed: %w", err)
 		}
+		d.setVerification(VerificationPassed)
 		log.Println("MD5 verification successful!")
+	} else if d.etag != "" {
+		log.Printf("ETag %q is not an MD5 sum, skipping MD5 verification.", d.etag)
 	} else {
 		log.Println("No ETag provided, skipping MD5 verification.")
 	}
 
-	elapsed := time.Since(d.startTime)
-	log.Printf("Total download time: %s", elapsed)
+	log.Printf("Total download time: %s", time.Since(d.startTime))
 
 	return nil
 }
 
+// downloadChunks downloads chunks in parallel until they are done or ctx
+// ends, and returns the remaining parts of the chunks paused by ctx.
+func (d *Downloader) downloadChunks(ctx context.Context, chunks []Chunk) []Chunk {
+	var (
+		pausedMu sync.Mutex
+		paused   []Chunk
+	)
+
+	// Semaphore to limit the number of concurrent goroutines.
+	// Tokens are slot numbers, so each goroutine can use its own client.
+	sem := make(chan int, d.NumGoroutines)
+	for slot := range d.NumGoroutines {
+		sem <- slot
+	}
+
+	for i, chunk := range chunks {
+		slot := <-sem // Acquire a token
+		if ctx.Err() != nil {
+			// Window closed or download cancelled, don't start new chunks
+			pausedMu.Lock()
+			paused = append(paused, chunks[i:]...)
+			pausedMu.Unlock()
+			sem <- slot
+			break
+		}
+
+		d.wg.Add(1)
+		go func(c Chunk) {
+			defer func() {
+				sem <- slot // Release the token
+				d.wg.Done()
+			}()
+			if rest, ok := d.downloadChunkWith(ctx, d.clientFor(slot), c); ok {
+				pausedMu.Lock()
+				paused = append(paused, rest)
+				pausedMu.Unlock()
+			}
+		}(chunk)
+	}
+
+	d.wg.Wait() // Wait for all download goroutines to finish
+
+	if d.ctx.Err() != nil {
+		return nil // Cancelled, nothing to resume
+	}
+	return paused
+}
+
 // getMetadata performs a HEAD request to get file size and ETag.
 func (d *Downloader) getMetadata() error {
 	req, err := http.NewRequestWithContext(d.ctx, "HEAD", d.URL, nil)
@@ -185,12 +270,15 @@ func (d *Downloader) getMetadata() error {
 		return fmt.Errorf("invalid Content-Length: %w", err)
 	}
 	d.fileSize = fileSize
+	d.proto = resp.Proto
 
 	d.etag = strings.Trim(resp.Header.Get("ETag"), `"`) // Remove quotes from ETag
+	d.modTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
 	return nil
 }
 
-// createEmptyFile creates the destination file and truncates it to the required size.
+// createEmptyFile creates the destination file and sizes it to the required size,
+// preallocating disk blocks when Preallocate is set.
 func (d *Downloader) createEmptyFile() error {
 	file, err := os.Create(d.DestFile)
 	if err != nil {
@@ -198,9 +286,9 @@ func (d *Downloader) createEmptyFile() error {
 	}
 	d.file = file
 
-	if err := d.file.Truncate(d.fileSize); err != nil {
+	if err := d.allocateFile(); err != nil {
 		d.file.Close() // Close file before returning error
-		return fmt.Errorf("failed to truncate file to size %d: %w", d.fileSize, err)
+		return fmt.Errorf("failed to size file to %d bytes: %w", d.fileSize, err)
 	}
 	return nil
 }
@@ -229,20 +317,37 @@ func (d *Downloader) calculateChunks() []Chunk {
 
 // downloadChunk downloads a specific chunk and writes it to the file.
 func (d *Downloader) downloadChunk(chunk Chunk) {
+	d.downloadChunkWith(d.ctx, d.client, chunk)
+}
+
+// downloadChunkWith downloads a chunk using the given client, streaming it
+// into the file. If ctx ends before d.ctx does (the transfer window closed),
+// it returns the part of the chunk that is still missing and true.
+func (d *Downloader) downloadChunkWith(ctx context.Context, client *http.Client, chunk Chunk) (Chunk, bool) {
+	paused := func() bool {
+		return ctx.Err() != nil && d.ctx.Err() == nil
+	}
+
 	attempt := 0
+	written := int64(0)
 	for attempt <= d.Retries {
 		select {
-		case <-d.ctx.Done():
+		case <-ctx.Done():
+			if paused() {
+				log.Printf("Chunk %d: Paused with %d bytes left.", chunk.ID, chunk.Size)
+				d.recordRetries(chunk.ID, attempt)
+				return chunk, true
+			}
 			log.Printf("Chunk %d: Download cancelled before starting or during retry.", chunk.ID)
-			return // Context cancelled, stop this goroutine
+			return chunk, false // Context cancelled, stop this goroutine
 		default:
 			// Continue
 		}
 
-		req, err := http.NewRequestWithContext(d.ctx, "GET", d.URL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", d.URL, nil)
 		if err != nil {
 			d.reportError(fmt.Errorf("chunk %d: failed to create GET request: %w", chunk.ID, err))
-			return
+			return chunk, false
 		}
 
 		// Set the Range header
@@ -252,52 +357,80 @@ func (d *Downloader) downloadChunk(chunk Chunk) {
 
 		log.Printf("Chunk %d: Attempt %d/%d. Downloading range %s...", chunk.ID, attempt+1, d.Retries+1, rangeHeader)
 
-		resp, err := d.client.Do(req)
+		resp, err := client.Do(req)
 		if err != nil {
+			if paused() {
+				continue // Reported at the top of the loop
+			}
 			log.Printf("Chunk %d: Download failed (attempt %d/%d): %v", chunk.ID, attempt+1, d.Retries+1, err)
 			attempt++
 			time.Sleep(time.Second * time.Duration(attempt)) // Exponential backoff
 			continue
 		}
-		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
-			err = fmt.Errorf("chunk %d: unexpected status code: %s", chunk.ID, resp.Status)
+		if err := checkPartialContent(resp, chunk.Offset); err != nil {
+			resp.Body.Close()
+			err = fmt.Errorf("chunk %d: %w", chunk.ID, err)
 			log.Printf("Chunk %d: Download failed (attempt %d/%d): %v", chunk.ID, attempt+1, d.Retries+1, err)
 			attempt++
 			time.Sleep(time.Second * time.Duration(attempt))
 			continue
 		}
 
-		// Read the content and write it to the file
+		// Stream the content into the file, keeping what was written if
+		// the transfer is interrupted
+		w := io.NewOffsetWriter(d.file, chunk.Offset)
+		n, err := io.Copy(w, io.LimitReader(resp.Body, chunk.Size))
+		resp.Body.Close()
+		written += n
+		chunk.Offset += n
+		chunk.Size -= n
 
-		data, err := io.ReadAll(resp.Body)
 		if err != nil {
-			continue
-		}
-		n, writeErr := d.file.WriteAt(data, chunk.Offset)
-		if writeErr != nil {
-			err = fmt.Errorf("chunk %d: failed to write to file: %w", chunk.ID, writeErr)
-			log.Printf("Chunk %d: Write failed (attempt %d/%d): %v", chunk.ID, attempt+1, d.Retries+1, err)
+			if paused() {
+				continue // Reported at the top of the loop
+			}
+			log.Printf("Chunk %d: Transfer failed (attempt %d/%d): %v", chunk.ID, attempt+1, d.Retries+1, err)
 			attempt++
 			time.Sleep(time.Second * time.Duration(attempt))
 			continue
 		}
 
-		if int64(n) != chunk.Size {
-			err = fmt.Errorf("chunk %d: incomplete write. Expected %d bytes, got %d", chunk.ID, chunk.Size, n)
+		if chunk.Size > 0 {
+			err = fmt.Errorf("chunk %d: incomplete body, %d bytes missing", chunk.ID, chunk.Size)
 			log.Printf("Chunk %d: Incomplete write (attempt %d/%d): %v", chunk.ID, attempt+1, d.Retries+1, err)
 			attempt++
 			time.Sleep(time.Second * time.Duration(attempt))
 			continue
 		}
 
-		log.Printf("Chunk %d: Downloaded and written %d bytes.", chunk.ID, n)
-		return // Success
+		log.Printf("Chunk %d: Downloaded and written %d bytes.", chunk.ID, written)
+		d.recordRetries(chunk.ID, attempt)
+		return chunk, false // Success
 	}
 
 	// If all retries fail
+	d.recordRetries(chunk.ID, d.Retries)
 	d.reportError(fmt.Errorf("chunk %d: failed after %d retries", chunk.ID, d.Retries))
+	return chunk, false
+}
+
+// checkPartialContent checks that resp answers a Range request starting at
+// offset. A server ignoring Range answers 200 with the whole file, which is
+// only usable for a range starting at the beginning of the file.
+func checkPartialContent(resp *http.Response, offset int64) error {
+	if resp.StatusCode == http.StatusOK && offset == 0 {
+		return nil
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("unexpected status code: %s", resp.Status)
+	}
+	contentRange := resp.Header.Get("Content-Range")
+	var start int64
+	if _, err := fmt.Sscanf(contentRange, "bytes %d-", &start); err != nil || start != offset {
+		return fmt.Errorf("unexpected Content-Range %q for a range starting at %d", contentRange, offset)
+	}
+	return nil
 }
 
 // reportError sends an error to the error channel and triggers cancellation.
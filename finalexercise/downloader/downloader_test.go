@@ -1,8 +1,9 @@
-package downloader_test
+package downloader
 
 import (
 	"bytes"
 	"context"
+	"crypto/md5"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,8 +14,6 @@ import (
 	"sync"
 	"testing"
 	"time"
-
-	"github.com/ArditZubaku/parallel-downloader/downloader"
 )
 
 // setupTestServer creates an HTTP test server that responds with file content.
@@ -95,60 +94,39 @@ func TestGetMetadata(t *testing.T) {
 	server := setupTestServer(t, testContent, testEtag, false)
 	defer server.Close()
 
-	d := downloader.NewDownloader(server.URL, "test_output.txt", 1, 1024, 0, 5*time.Second)
+	d := NewDownloader(server.URL, "test_output.txt", 1, 1024, 0, 5*time.Second)
 	// Initialize context for metadata fetch
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	d.SetContext(ctx, cancel) // Helper to set private fields for test
+	d.ctx, d.cancel = ctx, cancel
 
-	err := d.RunMetadataFetchOnly() // Separate method for testing metadata fetch
+	err := d.getMetadata()
 	if err != nil {
 		t.Fatalf("getMetadata failed: %v", err)
 	}
 
-	if d.GetFileSize() != int64(len(testContent)) {
-		t.Errorf("Expected file size %d, got %d", len(testContent), d.GetFileSize())
+	if d.fileSize != int64(len(testContent)) {
+		t.Errorf("Expected file size %d, got %d", len(testContent), d.fileSize)
 	}
-	if d.GetEtag() != testEtag {
-		t.Errorf("Expected ETag %q, got %q", testEtag, d.GetEtag())
+	if d.etag != testEtag {
+		t.Errorf("Expected ETag %q, got %q", testEtag, d.etag)
 	}
 }
 
-// Add a helper method to Downloader for testing metadata fetch in isolation
-func (d *downloader.Downloader) RunMetadataFetchOnly() error {
-	d.ctx, d.cancel = context.WithCancel(context.Background())
-	defer d.cancel()
-	return d.GetMetadata()
-}
-
-// Add getters for private fields for testing
-func (d *downloader.Downloader) GetFileSize() int64 {
-	return d.fileSize
-}
-
-func (d *downloader.Downloader) GetEtag() string {
-	return d.etag
-}
-
-func (d *downloader.Downloader) SetContext(ctx context.Context, cancel context.CancelFunc) {
-	d.ctx = ctx
-	d.cancel = cancel
-}
-
 // TestCreateEmptyFile tests the createEmptyFile function.
 func TestCreateEmptyFile(t *testing.T) {
 	destFile := "test_empty_file.tmp"
 	fileSize := int64(100)
 
-	d := downloader.NewDownloader("", destFile, 1, 10, 0, 5*time.Second)
-	d.SetFileSize(fileSize) // Helper to set private field for test
+	d := NewDownloader("", destFile, 1, 10, 0, 5*time.Second)
+	d.fileSize = fileSize
 
-	err := d.RunCreateEmptyFileOnly()
+	err := d.createEmptyFile()
 	if err != nil {
 		t.Fatalf("createEmptyFile failed: %v", err)
 	}
 	defer os.Remove(destFile) // Clean up file
-	defer d.CloseFile()       // Close the file handle used by the downloader
+	defer d.file.Close()      // Close the file handle used by the downloader
 
 	stat, err := os.Stat(destFile)
 	if err != nil {
@@ -159,33 +137,17 @@ func TestCreateEmptyFile(t *testing.T) {
 	}
 }
 
-// Add helper methods to Downloader for testing createEmptyFile in isolation
-func (d *downloader.Downloader) SetFileSize(size int64) {
-	d.fileSize = size
-}
-
-func (d *downloader.Downloader) RunCreateEmptyFileOnly() error {
-	return d.CreateEmptyFile()
-}
-
-func (d *downloader.Downloader) CloseFile() {
-	if d.file != nil {
-		d.file.Close()
-		d.file = nil
-	}
-}
-
 // TestCalculateChunks tests the calculateChunks function.
 func TestCalculateChunks(t *testing.T) {
 	tests := []struct {
 		fileSize  int64
 		chunkSize int64
-		expected  []downloader.Chunk
+		expected  []Chunk
 	}{
 		{
 			fileSize:  10,
 			chunkSize: 3,
-			expected: []downloader.Chunk{
+			expected: []Chunk{
 				{ID: 0, Offset: 0, Size: 3},
 				{ID: 1, Offset: 3, Size: 3},
 				{ID: 2, Offset: 6, Size: 3},
@@ -195,28 +157,28 @@ func TestCalculateChunks(t *testing.T) {
 		{
 			fileSize:  10,
 			chunkSize: 10,
-			expected: []downloader.Chunk{
+			expected: []Chunk{
 				{ID: 0, Offset: 0, Size: 10},
 			},
 		},
 		{
 			fileSize:  0,
 			chunkSize: 100,
-			expected:  []downloader.Chunk{},
+			expected:  []Chunk{},
 		},
 		{
 			fileSize:  1,
 			chunkSize: 100,
-			expected: []downloader.Chunk{
+			expected: []Chunk{
 				{ID: 0, Offset: 0, Size: 1},
 			},
 		},
 	}
 
 	for _, tt := range tests {
-		d := downloader.NewDownloader("", "", 1, tt.chunkSize, 0, 0)
-		d.SetFileSize(tt.fileSize) // Helper to set private field for test
-		chunks := d.CalculateChunks()
+		d := NewDownloader("", "", 1, tt.chunkSize, 0, 0)
+		d.fileSize = tt.fileSize
+		chunks := d.calculateChunks()
 
 		if len(chunks) != len(tt.expected) {
 			t.Fatalf("For fileSize %d, chunkSize %d: Expected %d chunks, got %d",
@@ -231,51 +193,41 @@ func TestCalculateChunks(t *testing.T) {
 	}
 }
 
-// Helper methods for testing calculateChunks
-func (d *downloader.Downloader) SetEtag(etag string) {
-	d.etag = etag
-}
-
-func (d *downloader.Downloader) CalculateChunks() []downloader.Chunk {
-	// Expose the private method for testing
-	return d.calculateChunks()
-}
-
 // TestDownloadChunkSuccess tests a single chunk download.
 func TestDownloadChunkSuccess(t *testing.T) {
 	testContent := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
-	testChunk := downloader.Chunk{ID: 0, Offset: 5, Size: 10} // "56789abcde"
+	testChunk := Chunk{ID: 0, Offset: 5, Size: 10} // "56789abcde"
 	destFile := "test_chunk_success.tmp"
 
 	server := setupTestServer(t, testContent, "", false)
 	defer server.Close()
 
-	d := downloader.NewDownloader(server.URL, destFile, 1, 10, 0, 5*time.Second)
-	d.SetFileSize(int64(len(testContent)))
+	d := NewDownloader(server.URL, destFile, 1, 10, 0, 5*time.Second)
+	d.fileSize = int64(len(testContent))
 
 	// Simulate setup for download, including creating the file
-	err := d.RunCreateEmptyFileOnly()
+	err := d.createEmptyFile()
 	if err != nil {
 		t.Fatalf("Failed to create empty file: %v", err)
 	}
 	defer os.Remove(destFile)
-	defer d.CloseFile()
+	defer d.file.Close()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	d.SetContext(ctx, cancel)
+	d.ctx, d.cancel = ctx, cancel
 	defer cancel()
 
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		d.DownloadChunk(testChunk) // Expose for testing
+		d.downloadChunk(testChunk)
 	}()
 	wg.Wait()
 
 	// Check if any error was reported through errChan (should not be for success)
 	select {
-	case err := <-d.GetErrChan():
+	case err := <-d.errChan:
 		t.Fatalf("Unexpected error received: %v", err)
 	default:
 		// No error
@@ -283,7 +235,7 @@ func TestDownloadChunkSuccess(t *testing.T) {
 
 	// Read content from the file and verify the chunk
 	fileContent := make([]byte, testChunk.Size)
-	n, err := d.GetFile().ReadAt(fileContent, testChunk.Offset)
+	n, err := d.file.ReadAt(fileContent, testChunk.Offset)
 	if err != nil && err != io.EOF {
 		t.Fatalf("Failed to read from file: %v", err)
 	}
@@ -298,7 +250,7 @@ func TestDownloadChunkSuccess(t *testing.T) {
 // TestDownloadChunkRetry tests the retry mechanism for chunk downloads.
 func TestDownloadChunkRetry(t *testing.T) {
 	testContent := []byte("0123456789")
-	testChunk := downloader.Chunk{ID: 0, Offset: 0, Size: 10}
+	testChunk := Chunk{ID: 0, Offset: 0, Size: 10}
 	destFile := "test_chunk_retry.tmp"
 	failCount := 0
 	maxFails := 1 // Server will fail once, then succeed
@@ -324,31 +276,31 @@ func TestDownloadChunkRetry(t *testing.T) {
 	}))
 	defer server.Close()
 
-	d := downloader.NewDownloader(server.URL, destFile, 1, 10, 2, 5*time.Second) // 2 retries means 3 attempts total
-	d.SetFileSize(int64(len(testContent)))
+	d := NewDownloader(server.URL, destFile, 1, 10, 2, 5*time.Second) // 2 retries means 3 attempts total
+	d.fileSize = int64(len(testContent))
 
-	err := d.RunCreateEmptyFileOnly()
+	err := d.createEmptyFile()
 	if err != nil {
 		t.Fatalf("Failed to create empty file: %v", err)
 	}
 	defer os.Remove(destFile)
-	defer d.CloseFile()
+	defer d.file.Close()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	d.SetContext(ctx, cancel)
+	d.ctx, d.cancel = ctx, cancel
 	defer cancel()
 
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		d.DownloadChunk(testChunk)
+		d.downloadChunk(testChunk)
 	}()
 	wg.Wait()
 
 	// Ensure no error was sent to errChan, as it should have succeeded on retry
 	select {
-	case err := <-d.GetErrChan():
+	case err := <-d.errChan:
 		t.Fatalf("Unexpected error received: %v", err)
 	default:
 		// No error, good
@@ -368,7 +320,7 @@ func TestDownloadChunkRetry(t *testing.T) {
 // TestDownloadChunkFailureAfterRetries tests if chunk download fails after all retries.
 func TestDownloadChunkFailureAfterRetries(t *testing.T) {
 	testContent := []byte("0123456789")
-	testChunk := downloader.Chunk{ID: 0, Offset: 0, Size: 10}
+	testChunk := Chunk{ID: 0, Offset: 0, Size: 10}
 	destFile := "test_chunk_fail_retries.tmp"
 
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -383,31 +335,31 @@ func TestDownloadChunkFailureAfterRetries(t *testing.T) {
 	}))
 	defer server.Close()
 
-	d := downloader.NewDownloader(server.URL, destFile, 1, 10, 1, 5*time.Second) // 1 retry means 2 attempts total
-	d.SetFileSize(int64(len(testContent)))
+	d := NewDownloader(server.URL, destFile, 1, 10, 1, 5*time.Second) // 1 retry means 2 attempts total
+	d.fileSize = int64(len(testContent))
 
-	err := d.RunCreateEmptyFileOnly()
+	err := d.createEmptyFile()
 	if err != nil {
 		t.Fatalf("Failed to create empty file: %v", err)
 	}
 	defer os.Remove(destFile)
-	defer d.CloseFile()
+	defer d.file.Close()
 
 	ctx, cancel := context.WithCancel(context.Background())
-	d.SetContext(ctx, cancel)
+	d.ctx, d.cancel = ctx, cancel
 	defer cancel()
 
 	var wg sync.WaitGroup
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		d.DownloadChunk(testChunk)
+		d.downloadChunk(testChunk)
 	}()
 	wg.Wait()
 
 	// Expect an error to be sent to errChan
 	select {
-	case err := <-d.GetErrChan():
+	case err := <-d.errChan:
 		if !strings.Contains(err.Error(), "failed after 1 retries") {
 			t.Errorf("Expected 'failed after 1 retries' error, got: %v", err)
 		}
@@ -416,18 +368,31 @@ func TestDownloadChunkFailureAfterRetries(t *testing.T) {
 	}
 }
 
-// Add helper methods to Downloader for testing downloadChunk
-func (d *downloader.Downloader) DownloadChunk(chunk downloader.Chunk) {
-	// Expose the private method for testing
-	d.downloadChunk(chunk)
-}
+// TestDownloadIgnoredRange tests that a server answering Range requests with
+// the whole file fails the download instead of corrupting it.
+func TestDownloadIgnoredRange(t *testing.T) {
+	testContent := []byte("0123456789abcdefghijklmnopqrstuvwxyz")
+	destFile := "test_ignored_range.tmp"
 
-func (d *downloader.Downloader) GetErrChan() <-chan error {
-	return d.errChan
-}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(testContent)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == "GET" {
+			_, _ = w.Write(testContent)
+		}
+	}))
+	defer server.Close()
 
-func (d *downloader.Downloader) GetFile() *os.File {
-	return d.file
+	d := NewDownloader(server.URL, destFile, 3, 12, 0, 5*time.Second)
+	err := d.Run()
+	defer os.Remove(destFile)
+	if err == nil {
+		content, _ := os.ReadFile(destFile)
+		t.Fatalf("Expected download to fail, but it saved %q", content)
+	}
+	if !strings.Contains(err.Error(), "failed after 0 retries") {
+		t.Errorf("Expected 'failed after 0 retries' error, got: %v", err)
+	}
 }
 
 // TestParallelDownloadFull tests the full parallel download process.
@@ -436,13 +401,13 @@ func TestParallelDownloadFull(t *testing.T) {
 	for i := 0; i < len(testContent); i++ {
 		testContent[i] = byte(i % 256)
 	}
-	testEtag := "d41d8cd98f00b204e9800998ecf8427e" // MD5 of an empty string for simplicity in ETag
+	testEtag := fmt.Sprintf("%x", md5.Sum(testContent)) // MD5 ETags are verified
 	destFile := "test_full_download.tmp"
 
 	server := setupTestServer(t, testContent, testEtag, false)
 	defer server.Close()
 
-	d := downloader.NewDownloader(server.URL, destFile, 4, 512*1024, 1, 10*time.Second) // 4 goroutines, 512KB chunks
+	d := NewDownloader(server.URL, destFile, 4, 512*1024, 1, 10*time.Second) // 4 goroutines, 512KB chunks
 	err := d.Run()
 	if err != nil {
 		t.Fatalf("Parallel download failed: %v", err)
@@ -485,7 +450,7 @@ func TestCleanupOnError(t *testing.T) {
 	}))
 	defer server.Close()
 
-	d := downloader.NewDownloader(server.URL, destFile, 1, 512, 0, 5*time.Second) // No retries for quick failure
+	d := NewDownloader(server.URL, destFile, 1, 512, 0, 5*time.Second) // No retries for quick failure
 
 	// Create an empty file to simulate partial download
 	initialFile, err := os.Create(destFile)
@@ -524,12 +489,13 @@ func TestGetFilenameFromURL(t *testing.T) {
 		{"http://example.com?file=mydata.txt", "mydata.txt"},
 		{"http://example.com?name=document.pdf", "document.pdf"},
 		{"http://example.com/query?id=123", "query"}, // Should prioritize path over generic query
-		{"", ""}, // Invalid URL
+		{"", "downloaded_file"},                      // Empty path, default fallback
+		{"://missing-scheme", ""},                    // Invalid URL
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.url, func(t *testing.T) {
-			result := downloader.GetFilenameFromURL(tt.url)
+			result := GetFilenameFromURL(tt.url)
 			if tt.expected == "downloaded_file" && !strings.HasPrefix(result, tt.expected) {
 				t.Errorf("For URL %s, expected prefix %s, got %s", tt.url, tt.expected, result)
 			} else if tt.expected != "downloaded_file" && result != tt.expected {
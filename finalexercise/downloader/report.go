@@ -0,0 +1,215 @@
+package downloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"time"
+)
+
+// Verification methods and results used in Plan and Report.
+const (
+	VerifyMD5  = "md5"
+	VerifyNone = "none"
+
+	VerificationPassed  = "passed"
+	VerificationFailed  = "failed"
+	VerificationSkipped = "skipped"
+)
+
+// md5Re matches ETags that are plain MD5 sums. Servers using other ETag
+// schemes (inode-mtime, multipart S3 uploads) can't be verified.
+var md5Re = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+
+// Plan describes what Run would do, without transferring any file data.
+type Plan struct {
+	URL          string
+	DestFile     string
+	FileSize     int64
+	ETag         string
+	LastModified time.Time // Zero if the server doesn't say
+	Protocol     string
+	ConnMode     string
+	ChunkSize    int64
+	Concurrency  int
+	Chunks       []Chunk
+	Verification string
+	Window       *Window
+}
+
+// Plan fetches the file metadata and returns the chunk plan for the download.
+func (d *Downloader) Plan() (*Plan, error) {
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	defer d.cancel()
+
+	if err := d.setupClients(); err != nil {
+		return nil, err
+	}
+	if err := d.getMetadata(); err != nil {
+		return nil, fmt.Errorf("failed to get file metadata: %w", err)
+	}
+
+	concurrency := d.NumGoroutines
+	chunks := d.calculateChunks()
+	if len(chunks) < concurrency {
+		concurrency = len(chunks) // Never more workers than chunks
+	}
+
+	return &Plan{
+		URL:          d.URL,
+		DestFile:     d.DestFile,
+		FileSize:     d.fileSize,
+		ETag:         d.etag,
+		LastModified: d.modTime,
+		Protocol:     d.proto,
+		ConnMode:     d.ConnMode,
+		ChunkSize:    d.ChunkSize,
+		Concurrency:  concurrency,
+		Chunks:       chunks,
+		Verification: d.verificationMethod(),
+		Window:       d.Window,
+	}, nil
+}
+
+// Print writes a human readable summary of the plan to w.
+func (p *Plan) Print(w io.Writer) {
+	fmt.Fprintf(w, "URL:          %s\n", p.URL)
+	fmt.Fprintf(w, "Destination:  %s\n", p.DestFile)
+	fmt.Fprintf(w, "File size:    %d bytes\n", p.FileSize)
+	fmt.Fprintf(w, "Chunks:       %d\n", len(p.Chunks))
+	if n := len(p.Chunks); n > 0 {
+		fmt.Fprintf(w, "Chunk size:   %d bytes (last chunk %d bytes)\n", p.ChunkSize, p.Chunks[n-1].Size)
+	}
+	fmt.Fprintf(w, "Concurrency:  %d goroutines\n", p.Concurrency)
+	fmt.Fprintf(w, "Protocol:     %s (connection mode %s)\n", p.Protocol, p.ConnMode)
+	if p.Verification == VerifyMD5 {
+		fmt.Fprintf(w, "Verification: %s (ETag %s)\n", p.Verification, p.ETag)
+	} else {
+		fmt.Fprintf(w, "Verification: %s\n", p.Verification)
+	}
+	if p.Window != nil {
+		fmt.Fprintf(w, "Window:       %s (next start %s)\n", p.Window, p.Window.NextOpen(time.Now()).Format(time.DateTime))
+	}
+}
+
+// Report is the machine readable summary of a finished (or failed) Run.
+type Report struct {
+	URL             string    `json:"url"`
+	DestFile        string    `json:"dest_file"`
+	FileSize        int64     `json:"file_size"`
+	Protocol        string    `json:"protocol"`
+	ConnMode        string    `json:"conn_mode"`
+	Chunks          int       `json:"chunks"`
+	StartTime       time.Time `json:"start_time"`
+	DurationSeconds float64   `json:"duration_seconds"` // Transfer time, without WaitSeconds
+	WaitSeconds     float64   `json:"wait_seconds"`     // Time spent waiting for the transfer window
+	Throughput      float64   `json:"throughput_bytes_per_sec"`
+	RetriesPerChunk []int     `json:"retries_per_chunk"`
+	TotalRetries    int       `json:"total_retries"`
+	Verification    string    `json:"verification"`
+	Error           string    `json:"error,omitempty"`
+}
+
+// Report returns the summary of the last call to Run.
+func (d *Downloader) Report() Report {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	r := Report{
+		URL:             d.URL,
+		DestFile:        d.DestFile,
+		FileSize:        d.fileSize,
+		Protocol:        d.proto,
+		ConnMode:        d.ConnMode,
+		Chunks:          d.numChunks,
+		StartTime:       d.startTime,
+		DurationSeconds: (d.elapsed - d.waited).Seconds(),
+		WaitSeconds:     d.waited.Seconds(),
+		RetriesPerChunk: make([]int, d.numChunks),
+		Verification:    d.verification,
+	}
+	if r.Verification == "" {
+		r.Verification = VerificationSkipped
+	}
+	if d.runErr != nil {
+		r.Error = d.runErr.Error()
+	}
+	if r.DurationSeconds > 0 && d.runErr == nil {
+		r.Throughput = float64(d.fileSize) / r.DurationSeconds
+	}
+	for id, n := range d.chunkRetries {
+		if id >= 0 && id < len(r.RetriesPerChunk) {
+			r.RetriesPerChunk[id] = n
+		}
+		r.TotalRetries += n
+	}
+	return r
+}
+
+// WriteReport writes the JSON report of the last call to Run to path.
+func (d *Downloader) WriteReport(path string) error {
+	data, err := json.MarshalIndent(d.Report(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// verificationMethod reports how the downloaded file will be verified.
+func (d *Downloader) verificationMethod() string {
+	if md5Re.MatchString(d.etag) {
+		return VerifyMD5
+	}
+	return VerifyNone
+}
+
+// recordRetries adds the retries of one download attempt of a chunk, so a
+// chunk resumed after a window pause counts the retries of every window.
+func (d *Downloader) recordRetries(chunkID, retries int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.chunkRetries == nil {
+		d.chunkRetries = make(map[int]int)
+	}
+	d.chunkRetries[chunkID] += retries
+}
+
+// resetStats clears the statistics of a previous Run.
+func (d *Downloader) resetStats() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.startTime = time.Now()
+	d.numChunks = 0
+	d.chunkRetries = nil
+	d.verification = ""
+	d.elapsed = 0
+	d.waited = 0
+	d.runErr = nil
+}
+
+// finishStats records the outcome of Run.
+func (d *Downloader) finishStats(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.elapsed = time.Since(d.startTime)
+	d.runErr = err
+}
+
+// addWait records time spent waiting for the transfer window.
+func (d *Downloader) addWait(wait time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.waited += wait
+}
+
+func (d *Downloader) setVerification(result string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.verification = result
+}
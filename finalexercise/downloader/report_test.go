@@ -0,0 +1,181 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyServer serves content with range support, failing the first
+// `fails` GET requests with a 500.
+func newFlakyServer(t *testing.T, content []byte, fails int32) *httptest.Server {
+	sum := md5.Sum(content)
+	etag := hex.EncodeToString(sum[:])
+	var failed atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", fmt.Sprintf(`"%s"`, etag))
+		if r.Method == http.MethodGet && failed.Load() < fails {
+			failed.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPlan(t *testing.T) {
+	content := make([]byte, 2500)
+	server := newFlakyServer(t, content, 0)
+
+	d := NewDownloader(server.URL, filepath.Join(t.TempDir(), "plan.bin"), 4, 1000, 0, 5*time.Second)
+	plan, err := d.Plan()
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if plan.FileSize != 2500 || len(plan.Chunks) != 3 {
+		t.Errorf("Expected 2500 bytes in 3 chunks, got %d bytes in %d chunks", plan.FileSize, len(plan.Chunks))
+	}
+	if plan.Concurrency != 3 {
+		t.Errorf("Expected concurrency capped at 3, got %d", plan.Concurrency)
+	}
+	if plan.Verification != VerifyMD5 {
+		t.Errorf("Expected verification %q, got %q", VerifyMD5, plan.Verification)
+	}
+	if _, err := os.Stat(d.DestFile); !os.IsNotExist(err) {
+		t.Errorf("Plan must not create %s", d.DestFile)
+	}
+}
+
+func TestWriteReport(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	server := newFlakyServer(t, content, 1)
+	dir := t.TempDir()
+
+	d := NewDownloader(server.URL, filepath.Join(dir, "out.bin"), 1, 500, 2, 5*time.Second)
+	if err := d.Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	reportPath := filepath.Join(dir, "report.json")
+	if err := d.WriteReport(reportPath); err != nil {
+		t.Fatalf("WriteReport failed: %v", err)
+	}
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Failed to read report: %v", err)
+	}
+
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		t.Fatalf("Invalid report JSON: %v", err)
+	}
+	if r.Chunks != 2 || len(r.RetriesPerChunk) != 2 {
+		t.Errorf("Expected 2 chunks in report, got %d (%v)", r.Chunks, r.RetriesPerChunk)
+	}
+	if r.TotalRetries != 1 {
+		t.Errorf("Expected 1 retry in total, got %d", r.TotalRetries)
+	}
+	if r.Verification != VerificationPassed {
+		t.Errorf("Expected verification %q, got %q", VerificationPassed, r.Verification)
+	}
+	if r.Error != "" || r.Throughput <= 0 {
+		t.Errorf("Expected successful report, got error %q, throughput %f", r.Error, r.Throughput)
+	}
+}
+
+func TestReportExcludesWindowWait(t *testing.T) {
+	d := NewDownloader("", "out.bin", 1, 500, 0, time.Second)
+	d.fileSize = 1000
+	d.elapsed = 10 * time.Second
+	d.waited = 8 * time.Second
+
+	r := d.Report()
+	if r.DurationSeconds != 2 || r.WaitSeconds != 8 {
+		t.Errorf("Expected 2s transfer and 8s wait, got %fs and %fs", r.DurationSeconds, r.WaitSeconds)
+	}
+	if r.Throughput != 500 {
+		t.Errorf("Expected throughput 500 bytes/s, got %f", r.Throughput)
+	}
+}
+
+func TestReportRetriesAcrossResumes(t *testing.T) {
+	content := []byte("0123456789")
+	var gets atomic.Int32
+	// Fail the first GET of every resume
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && gets.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	d := NewDownloader(server.URL, filepath.Join(t.TempDir(), "out.bin"), 1, 10, 1, 5*time.Second)
+	d.fileSize = int64(len(content))
+	if err := d.createEmptyFile(); err != nil {
+		t.Fatalf("Failed to create empty file: %v", err)
+	}
+	defer d.file.Close()
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	defer d.cancel()
+
+	chunk := Chunk{ID: 0, Offset: 0, Size: int64(len(content))}
+	for range 2 {
+		if _, paused := d.downloadChunkWith(d.ctx, d.client, chunk); paused {
+			t.Fatal("Chunk unexpectedly paused")
+		}
+	}
+	if d.chunkRetries[0] != 2 {
+		t.Errorf("Expected 2 retries over both resumes, got %d", d.chunkRetries[0])
+	}
+}
+
+func TestVerificationByETag(t *testing.T) {
+	content := []byte("0123456789")
+	other := md5.Sum([]byte("9876543210"))
+
+	tests := []struct {
+		name    string
+		etag    string
+		wantErr bool
+		want    string
+	}{
+		{"no ETag", "", false, VerificationSkipped},
+		{"non-MD5 ETag", "5f1-abc", false, VerificationSkipped},
+		{"mismatching MD5 ETag", hex.EncodeToString(other[:]), true, VerificationFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.etag != "" {
+					w.Header().Set("ETag", fmt.Sprintf(`"%s"`, tt.etag))
+				}
+				http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+			}))
+			defer server.Close()
+
+			d := NewDownloader(server.URL, filepath.Join(t.TempDir(), "out.bin"), 1, 10, 0, 5*time.Second)
+			if err := d.Run(); (err != nil) != tt.wantErr {
+				t.Fatalf("Run error = %v, want error %v", err, tt.wantErr)
+			}
+			if got := d.Report().Verification; got != tt.want {
+				t.Errorf("Expected verification %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
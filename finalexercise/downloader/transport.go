@@ -0,0 +1,83 @@
+package downloader
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Connection modes for Downloader.ConnMode.
+const (
+	// ConnAuto shares one connection pool sized to NumGoroutines. If the
+	// server speaks HTTP/2, all chunks are multiplexed on one connection.
+	ConnAuto = "auto"
+	// ConnHTTP1 disables HTTP/2 so every goroutine gets its own TCP connection.
+	ConnHTTP1 = "http1"
+	// ConnHTTP2 gives every goroutine its own transport, and so its own
+	// HTTP/2 connection.
+	ConnHTTP2 = "http2"
+)
+
+// newTransport returns a transport whose pools match the number of goroutines.
+func newTransport(maxConns int, timeout time.Duration, tlsConfig *tls.Config, http1Only bool) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxConnsPerHost = maxConns
+	t.MaxIdleConnsPerHost = maxConns
+	t.MaxIdleConns = maxConns
+	if timeout > 0 {
+		t.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+		t.TLSHandshakeTimeout = timeout
+	}
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig.Clone()
+	}
+	if http1Only {
+		t.Protocols = new(http.Protocols)
+		t.Protocols.SetHTTP1(true)
+	}
+	return t
+}
+
+// setupClients builds the HTTP clients for the configured ConnMode.
+func (d *Downloader) setupClients() error {
+	n := max(d.NumGoroutines, 1)
+
+	switch d.ConnMode {
+	case "", ConnAuto:
+		d.client = &http.Client{Timeout: d.Timeout, Transport: newTransport(n, d.Timeout, d.tlsConfig, false)}
+		d.clients = nil
+	case ConnHTTP1:
+		d.client = &http.Client{Timeout: d.Timeout, Transport: newTransport(n, d.Timeout, d.tlsConfig, true)}
+		d.clients = nil
+	case ConnHTTP2:
+		d.clients = make([]*http.Client, n)
+		for i := range d.clients {
+			d.clients[i] = &http.Client{Timeout: d.Timeout, Transport: newTransport(1, d.Timeout, d.tlsConfig, false)}
+		}
+		d.client = d.clients[0]
+	default:
+		return fmt.Errorf("unknown connection mode %q (want %s, %s or %s)", d.ConnMode, ConnAuto, ConnHTTP1, ConnHTTP2)
+	}
+	return nil
+}
+
+// clientFor returns the client used by the goroutine holding the given slot.
+func (d *Downloader) clientFor(slot int) *http.Client {
+	if len(d.clients) == 0 {
+		return d.client
+	}
+	return d.clients[slot%len(d.clients)]
+}
+
+// logProtocol reports the negotiated protocol and warns when HTTP/2
+// multiplexing would serialize the parallel chunk requests.
+func (d *Downloader) logProtocol() {
+	log.Printf("Server negotiated %s", d.proto)
+	if d.proto == "HTTP/2.0" && (d.ConnMode == "" || d.ConnMode == ConnAuto) && d.NumGoroutines > 1 {
+		log.Printf("All %d goroutines share one HTTP/2 connection. Use connection mode %q or %q for separate connections.",
+			d.NumGoroutines, ConnHTTP1, ConnHTTP2)
+	}
+}
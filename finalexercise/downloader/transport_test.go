@@ -0,0 +1,75 @@
+package downloader
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTLSServer starts an HTTP/2 capable TLS server and counts new connections.
+func newTLSServer(t *testing.T, content []byte, conns *atomic.Int32) *httptest.Server {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			time.Sleep(100 * time.Millisecond) // Make the chunk requests overlap
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+	server.EnableHTTP2 = true
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestConnModes(t *testing.T) {
+	content := bytes.Repeat([]byte("x"), 4096)
+
+	tests := []struct {
+		mode      string
+		proto     string
+		wantConns int32
+	}{
+		{ConnAuto, "HTTP/2.0", 1},
+		{ConnHTTP1, "HTTP/1.1", 2},
+		{ConnHTTP2, "HTTP/2.0", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			var conns atomic.Int32
+			server := newTLSServer(t, content, &conns)
+
+			d := NewDownloader(server.URL, filepath.Join(t.TempDir(), "out.bin"), 2, 2048, 0, 5*time.Second)
+			d.ConnMode = tt.mode
+			d.tlsConfig = &tls.Config{RootCAs: server.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}
+
+			if err := d.Run(); err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+			if d.proto != tt.proto {
+				t.Errorf("Expected protocol %s, got %s", tt.proto, d.proto)
+			}
+			if got := conns.Load(); got != tt.wantConns {
+				t.Errorf("Expected %d connections, got %d", tt.wantConns, got)
+			}
+		})
+	}
+}
+
+func TestUnknownConnMode(t *testing.T) {
+	d := NewDownloader("http://example.com/file", "file", 1, 1024, 0, time.Second)
+	d.ConnMode = "carrier-pigeon"
+	if err := d.setupClients(); err == nil {
+		t.Fatal("Expected error for unknown connection mode")
+	}
+}
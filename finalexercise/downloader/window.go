@@ -0,0 +1,138 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Window is a daily local time range during which transfers may run.
+// A window whose end is before its start wraps past midnight, like 22:00-06:00.
+type Window struct {
+	Start time.Duration // Offset from midnight
+	End   time.Duration // Offset from midnight
+}
+
+// ParseWindow parses a window in the form "HH:MM-HH:MM" (seconds optional).
+func ParseWindow(s string) (*Window, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return nil, fmt.Errorf("bad window %q: want HH:MM-HH:MM", s)
+	}
+
+	start, err := parseClock(from)
+	if err != nil {
+		return nil, fmt.Errorf("bad window start %q: %w", from, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return nil, fmt.Errorf("bad window end %q: %w", to, err)
+	}
+	if start == end {
+		return nil, fmt.Errorf("bad window %q: start and end are equal", s)
+	}
+	return &Window{Start: start, End: end}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"15:04", "15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour +
+				time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, nil
+		}
+	}
+	return 0, fmt.Errorf("want HH:MM or HH:MM:SS")
+}
+
+func (w *Window) String() string {
+	if w == nil {
+		return "always"
+	}
+	return formatClock(w.Start) + "-" + formatClock(w.End)
+}
+
+func formatClock(d time.Duration) string {
+	h, m, s := int(d/time.Hour), int(d/time.Minute)%60, int(d/time.Second)%60
+	if s != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", h, m)
+}
+
+// at returns the wall clock time d (an offset from midnight) on t's day, in
+// t's location. On daylight saving days that is not midnight plus d.
+func at(t time.Time, d time.Duration) time.Time {
+	y, mo, day := t.Date()
+	h, m, s := int(d/time.Hour), int(d/time.Minute)%60, int(d/time.Second)%60
+	return time.Date(y, mo, day, h, m, s, 0, t.Location())
+}
+
+// Contains reports whether t falls inside the window.
+func (w *Window) Contains(t time.Time) bool {
+	start, end := at(t, w.Start), at(t, w.End)
+	if w.Start < w.End {
+		return !t.Before(start) && t.Before(end)
+	}
+	// Wraps past midnight
+	return !t.Before(start) || t.Before(end)
+}
+
+// NextOpen returns t if the window is open, otherwise the next time it opens.
+func (w *Window) NextOpen(t time.Time) time.Time {
+	if w.Contains(t) {
+		return t
+	}
+	start := at(t, w.Start)
+	if start.Before(t) {
+		start = at(t.AddDate(0, 0, 1), w.Start)
+	}
+	return start
+}
+
+// NextClose returns the next time after t at which the window closes.
+func (w *Window) NextClose(t time.Time) time.Time {
+	end := at(t, w.End)
+	if !end.After(t) {
+		end = at(t.AddDate(0, 0, 1), w.End)
+	}
+	return end
+}
+
+// waitForWindow blocks until the transfer window is open or the download
+// is cancelled.
+func (d *Downloader) waitForWindow() error {
+	if d.Window == nil {
+		return nil
+	}
+
+	now := time.Now()
+	open := d.Window.NextOpen(now)
+	if !open.After(now) {
+		return nil
+	}
+
+	log.Printf("Outside transfer window %s, waiting until %s...", d.Window, open.Format(time.DateTime))
+	timer := time.NewTimer(open.Sub(now))
+	defer timer.Stop()
+	defer func() { d.addWait(time.Since(now)) }()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-d.ctx.Done():
+		return d.ctx.Err()
+	}
+}
+
+// windowContext returns a context that ends when the current transfer
+// window closes.
+func (d *Downloader) windowContext() (context.Context, context.CancelFunc) {
+	if d.Window == nil {
+		return context.WithCancel(d.ctx)
+	}
+	return context.WithDeadline(d.ctx, d.Window.NextClose(time.Now()))
+}
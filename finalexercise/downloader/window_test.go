@@ -0,0 +1,166 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseWindow(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"22:00-06:00", "22:00-06:00", false},
+		{"09:30-17:45", "09:30-17:45", false},
+		{"01:00:30-01:00:45", "01:00:30-01:00:45", false},
+		{"22:00", "", true},
+		{"25:00-06:00", "", true},
+		{"06:00-06:00", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			w, err := ParseWindow(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Expected error for %q", tt.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWindow failed: %v", err)
+			}
+			if w.String() != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, w)
+			}
+		})
+	}
+}
+
+func TestWindowSchedule(t *testing.T) {
+	w, err := ParseWindow("22:00-06:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := func(h, m int) time.Time { return time.Date(2024, 5, 10, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		now       time.Time
+		open      bool
+		nextOpen  time.Time
+		nextClose time.Time
+	}{
+		{day(12, 0), false, day(22, 0), day(12, 0).Add(18 * time.Hour)},
+		{day(23, 0), true, day(23, 0), day(30, 0)},
+		{day(5, 59), true, day(5, 59), day(6, 0)},
+		{day(6, 0), false, day(22, 0), day(30, 0)},
+	}
+
+	for _, tt := range tests {
+		if got := w.Contains(tt.now); got != tt.open {
+			t.Errorf("Contains(%s) = %v, expected %v", tt.now, got, tt.open)
+		}
+		if got := w.NextOpen(tt.now); !got.Equal(tt.nextOpen) {
+			t.Errorf("NextOpen(%s) = %s, expected %s", tt.now, got, tt.nextOpen)
+		}
+		if got := w.NextClose(tt.now); !got.Equal(tt.nextClose) {
+			t.Errorf("NextClose(%s) = %s, expected %s", tt.now, got, tt.nextClose)
+		}
+	}
+}
+
+func TestWindowScheduleDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data not available: %v", err)
+	}
+	w, err := ParseWindow("06:00-08:00")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Clocks go forward at 02:00 on 2024-03-10 and back at 02:00 on 2024-11-03
+	for _, date := range []time.Time{
+		time.Date(2024, 3, 10, 0, 0, 0, 0, loc),
+		time.Date(2024, 11, 3, 0, 0, 0, 0, loc),
+	} {
+		y, mo, d := date.Date()
+		if got, want := w.NextOpen(date), time.Date(y, mo, d, 6, 0, 0, 0, loc); !got.Equal(want) {
+			t.Errorf("NextOpen(%s) = %s, expected %s", date, got, want)
+		}
+		if now := time.Date(y, mo, d, 7, 30, 0, 0, loc); !w.Contains(now) {
+			t.Errorf("Contains(%s) = false, expected true", now)
+		}
+		if now := time.Date(y, mo, d, 5, 30, 0, 0, loc); w.Contains(now) {
+			t.Errorf("Contains(%s) = true, expected false", now)
+		}
+	}
+}
+
+// TestDownloadChunksPauseResume closes the "window" mid-transfer and checks
+// that the paused chunks resume where they stopped.
+func TestDownloadChunksPauseResume(t *testing.T) {
+	content := make([]byte, 2000)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var start, end int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		// Trickle the body so the window closes mid-chunk
+		for i := start; i <= end; i += 100 {
+			w.Write(content[i:min(i+100, end+1)])
+			w.(http.Flusher).Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	defer server.Close()
+
+	d := NewDownloader(server.URL, filepath.Join(t.TempDir(), "out.bin"), 2, 1000, 0, 5*time.Second)
+	d.fileSize = int64(len(content))
+	if err := d.createEmptyFile(); err != nil {
+		t.Fatalf("createEmptyFile failed: %v", err)
+	}
+	defer d.file.Close()
+
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+	defer d.cancel()
+
+	winCtx, stop := context.WithTimeout(d.ctx, 70*time.Millisecond)
+	paused := d.downloadChunks(winCtx, d.calculateChunks())
+	stop()
+
+	if len(paused) != 2 {
+		t.Fatalf("Expected 2 paused chunks, got %+v", paused)
+	}
+	for _, c := range paused {
+		if c.Size <= 0 || c.Size >= 1000 {
+			t.Errorf("Expected chunk %d to be partially written, %d bytes left", c.ID, c.Size)
+		}
+	}
+
+	if rest := d.downloadChunks(d.ctx, paused); len(rest) != 0 {
+		t.Fatalf("Expected resumed download to finish, %d chunks left", len(rest))
+	}
+	data, err := os.ReadFile(d.DestFile)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Error("Resumed file content mismatch")
+	}
+}
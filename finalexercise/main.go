@@ -4,10 +4,13 @@ package main
 import (
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/ArditZubaku/parallel-downloader/downloader"
+	"github.com/ArditZubaku/parallel-downloader/mirror"
 	"github.com/urfave/cli/v2"
 )
 
@@ -37,12 +40,11 @@ func main() {
 	app := &cli.App{
 		Name:  "parallel-downloader",
 		Usage: "Download files over HTTP in parallel",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
-				Name:     "url",
-				Aliases:  []string{"u"},
-				Usage:    "URL of the file to download",
-				Required: true,
+				Name:    "url",
+				Aliases: []string{"u"},
+				Usage:   "URL of the file to download",
 			},
 			&cli.StringFlag{
 				Name:    "output",
@@ -50,38 +52,24 @@ func main() {
 				Usage:   "Output file name",
 				Value:   "", // Default will be derived from URL
 			},
-			&cli.IntFlag{
-				Name:    "goroutines",
-				Aliases: []string{"g"},
-				Usage:   "Number of parallel downloading goroutines",
-				Value:   4, // Default to 4 goroutines
-			},
-			&cli.Int64Flag{
-				Name:    "chunk-size",
-				Aliases: []string{"c"},
-				Usage:   "Size of each download chunk in bytes",
-				Value:   1024 * 1024 * 5, // Default to 5MB chunks
-			},
-			&cli.IntFlag{
-				Name:    "retries",
-				Aliases: []string{"r"},
-				Usage:   "Number of retries for a failed chunk download",
-				Value:   3, // Default to 3 retries
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Fetch metadata and print the download plan without downloading",
 			},
-			&cli.DurationFlag{
-				Name:    "timeout",
-				Aliases: []string{"t"},
-				Usage:   "Connection timeout for each HTTP request (e.g., 10s)",
-				Value:   30 * time.Second, // Default to 30 seconds timeout
+			&cli.StringFlag{
+				Name:  "report",
+				Usage: "Write a JSON summary of the download to this file",
 			},
-		},
+		}, transferFlags()...),
 		Action: func(c *cli.Context) error {
 			url := c.String("url")
 			output := c.String("output")
-			numGoroutines := c.Int("goroutines")
-			chunkSize := c.Int64("chunk-size")
-			retries := c.Int("retries")
-			timeout := c.Duration("timeout")
+			reportPath := c.String("report")
+
+			// Not marked Required, so that subcommands can run without it
+			if url == "" {
+				return cli.Exit("Required flag \"url\" not set", 1)
+			}
 
 			// If output filename is not provided, derive it from the URL
 			if output == "" {
@@ -92,21 +80,159 @@ func main() {
 				fmt.Printf("Output filename not specified, using: %s\n", output)
 			}
 
+			dl := newDownloader(c, url, output)
+
+			if c.Bool("dry-run") {
+				plan, err := dl.Plan()
+				if err != nil {
+					log.Fatalf("Planning failed: %v", err)
+				}
+				plan.Print(os.Stdout)
+				return nil
+			}
+
 			fmt.Printf("Starting download for %s to %s...\n", url, output)
 			fmt.Printf("Goroutines: %d, Chunk Size: %d bytes, Retries: %d, Timeout: %s\n",
-				numGoroutines, chunkSize, retries, timeout)
+				dl.NumGoroutines, dl.ChunkSize, dl.Retries, dl.Timeout)
 
-			dl := downloader.NewDownloader(url, output, numGoroutines, chunkSize, retries, timeout)
-			if err := dl.Run(); err != nil {
+			err := dl.Run()
+			if reportPath != "" {
+				// Write the report even when the download failed
+				if rerr := dl.WriteReport(reportPath); rerr != nil {
+					log.Printf("Failed to write report: %v", rerr)
+				}
+			}
+			if err != nil {
 				log.Fatalf("Download failed: %v", err)
 			}
 
 			fmt.Println("Download completed successfully!")
 			return nil
 		},
+		Commands: []*cli.Command{
+			mirrorCommand(),
+		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// transferFlags are the flags shared by single downloads and mirroring.
+func transferFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:    "goroutines",
+			Aliases: []string{"g"},
+			Usage:   "Number of parallel downloading goroutines",
+			Value:   4, // Default to 4 goroutines
+		},
+		&cli.Int64Flag{
+			Name:    "chunk-size",
+			Aliases: []string{"c"},
+			Usage:   "Size of each download chunk in bytes",
+			Value:   1024 * 1024 * 5, // Default to 5MB chunks
+		},
+		&cli.IntFlag{
+			Name:    "retries",
+			Aliases: []string{"r"},
+			Usage:   "Number of retries for a failed chunk download",
+			Value:   3, // Default to 3 retries
+		},
+		&cli.DurationFlag{
+			Name:    "timeout",
+			Aliases: []string{"t"},
+			Usage:   "Connection timeout for each HTTP request (e.g., 10s)",
+			Value:   30 * time.Second, // Default to 30 seconds timeout
+		},
+		&cli.BoolFlag{
+			Name:  "preallocate",
+			Usage: "Reserve disk space for the whole file before downloading (fallocate on Linux)",
+		},
+		&cli.StringFlag{
+			Name:  "window",
+			Usage: "Only transfer during this daily local time window (e.g., 22:00-06:00)",
+		},
+		&cli.StringFlag{
+			Name:  "conn-mode",
+			Usage: "Connection mode: auto (shared pool), http1 (one HTTP/1.1 connection per goroutine) or http2 (one HTTP/2 connection per goroutine)",
+			Value: downloader.ConnAuto,
+		},
+	}
+}
+
+// newDownloader creates a Downloader configured from the transfer flags.
+func newDownloader(c *cli.Context, url, output string) *downloader.Downloader {
+	dl := downloader.NewDownloader(url, output, c.Int("goroutines"), c.Int64("chunk-size"), c.Int("retries"), c.Duration("timeout"))
+	dl.Preallocate = c.Bool("preallocate")
+	dl.ConnMode = c.String("conn-mode")
+	if window := c.String("window"); window != "" {
+		w, err := downloader.ParseWindow(window)
+		if err != nil {
+			log.Fatalf("Invalid --window: %v", err)
+		}
+		dl.Window = w
+	}
+	return dl
+}
+
+func mirrorCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "mirror",
+		Usage:     "Download every file listed in an HTTP directory index (HTML autoindex or JSON)",
+		ArgsUsage: "INDEX_URL",
+		Flags: append([]cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Local directory to mirror into",
+				Value:   ".",
+			},
+			&cli.StringFlag{
+				Name:  "glob",
+				Usage: "Only download files whose name matches this glob (e.g., '*.parquet')",
+			},
+			&cli.StringFlag{
+				Name:  "regex",
+				Usage: "Only download files whose relative path matches this regular expression",
+			},
+			&cli.IntFlag{
+				Name:  "depth",
+				Usage: "How many levels of subdirectories to follow",
+				Value: 0,
+			},
+		}, transferFlags()...),
+		Action: func(c *cli.Context) error {
+			if c.NArg() != 1 {
+				return cli.Exit("mirror needs exactly one INDEX_URL argument", 1)
+			}
+
+			m := &mirror.Mirror{
+				URL:      c.Args().First(),
+				DestDir:  c.String("output"),
+				Glob:     c.String("glob"),
+				MaxDepth: c.Int("depth"),
+				Client:   &http.Client{Timeout: c.Duration("timeout")},
+				NewDownloader: func(url, destFile string) *downloader.Downloader {
+					return newDownloader(c, url, destFile)
+				},
+			}
+			if expr := c.String("regex"); expr != "" {
+				re, err := regexp.Compile(expr)
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("Bad regex: %v", err), 1)
+				}
+				m.Regexp = re
+			}
+
+			stats, err := m.Run()
+			fmt.Printf("Mirror finished: %d downloaded, %d skipped, %d failed\n",
+				stats.Downloaded, stats.Skipped, stats.Failed)
+			if err != nil {
+				log.Fatalf("Mirror failed: %v", err)
+			}
+			return nil
+		},
+	}
+}
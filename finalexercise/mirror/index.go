@@ -0,0 +1,135 @@
+package mirror
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Entry is a file or directory listed in a directory index.
+type Entry struct {
+	Name string // Last path segment, unescaped
+	URL  string // Absolute URL of the entry
+	Dir  bool
+	Size int64 // -1 when the listing doesn't say
+}
+
+var hrefRe = regexp.MustCompile(`(?i)<a\s[^>]*?href\s*=\s*["']([^"']+)["']`)
+
+// ParseIndex extracts the entries below base from an HTML autoindex page or
+// a JSON listing. Links to parent directories, sort links and links outside
+// base are dropped.
+func ParseIndex(base *url.URL, contentType string, body []byte) ([]Entry, error) {
+	if strings.Contains(contentType, "json") || looksLikeJSON(body) {
+		return parseJSON(base, body)
+	}
+	return parseHTML(base, body), nil
+}
+
+func looksLikeJSON(body []byte) bool {
+	s := strings.TrimSpace(string(body))
+	return strings.HasPrefix(s, "[")
+}
+
+func parseHTML(base *url.URL, body []byte) []Entry {
+	var entries []Entry
+	seen := make(map[string]bool)
+	for _, m := range hrefRe.FindAllSubmatch(body, -1) {
+		href := html.UnescapeString(string(m[1]))
+		e, ok := resolve(base, href)
+		if !ok || seen[e.URL] {
+			continue
+		}
+		seen[e.URL] = true
+		e.Size = -1
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// jsonEntry covers the nginx (autoindex_format json) and Caddy (browse)
+// listing formats.
+type jsonEntry struct {
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+	Type  string `json:"type"`
+	IsDir bool   `json:"is_dir"`
+	Size  *int64 `json:"size"`
+}
+
+func parseJSON(base *url.URL, body []byte) ([]Entry, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON listing: %w", err)
+	}
+
+	var entries []Entry
+	for _, r := range raw {
+		var je jsonEntry
+		// Plain list of names: ["a.txt", "sub/"]
+		if err := json.Unmarshal(r, &je.Name); err != nil {
+			if err := json.Unmarshal(r, &je); err != nil {
+				return nil, fmt.Errorf("invalid JSON listing entry %s: %w", r, err)
+			}
+		}
+
+		href := je.URL
+		if href == "" {
+			href = url.PathEscape(je.Name)
+		}
+		isDir := je.IsDir || je.Type == "directory" || strings.HasSuffix(href, "/")
+		if isDir && !strings.HasSuffix(href, "/") {
+			href += "/"
+		}
+
+		e, ok := resolve(base, href)
+		if !ok {
+			continue
+		}
+		e.Size = -1
+		if je.Size != nil {
+			e.Size = *je.Size
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// resolve turns href into an Entry if it points strictly below base.
+func resolve(base *url.URL, href string) (Entry, bool) {
+	if strings.HasPrefix(href, "?") || strings.HasPrefix(href, "#") {
+		return Entry{}, false // Sort links and anchors
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return Entry{}, false
+	}
+	u := base.ResolveReference(ref)
+	u.RawQuery, u.Fragment = "", ""
+
+	basePath := base.Path
+	if !strings.HasSuffix(basePath, "/") {
+		basePath = path.Dir(basePath) + "/"
+	}
+	if u.Scheme != base.Scheme || u.Host != base.Host || !strings.HasPrefix(u.Path, basePath) || u.Path == basePath {
+		return Entry{}, false // Parent directories and other sites
+	}
+
+	// The name is the last escaped segment, so escaped dots and slashes
+	// ("%2e%2e/", "a%2fb") can't climb out of the local directory.
+	escaped := strings.TrimSuffix(u.EscapedPath(), "/")
+	name, err := url.PathUnescape(escaped[strings.LastIndex(escaped, "/")+1:])
+	if err != nil || name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return Entry{}, false
+	}
+
+	return Entry{
+		Name: name,
+		URL:  u.String(),
+		Dir:  strings.HasSuffix(u.Path, "/"),
+	}, true
+}
@@ -0,0 +1,208 @@
+/*
+Package mirror downloads every file listed in an HTTP directory index into a
+local directory tree, using downloader.Downloader for each file.
+*/
+package mirror
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ArditZubaku/parallel-downloader/downloader"
+)
+
+// maxIndexSize caps how much of an index page is read.
+const maxIndexSize = 10 << 20
+
+// Mirror copies a remote directory index into DestDir.
+type Mirror struct {
+	URL      string         // Index URL, should end with "/"
+	DestDir  string         // Local root directory
+	Glob     string         // Only files whose name matches, "" for all
+	Regexp   *regexp.Regexp // Only files whose relative path matches, nil for all
+	MaxDepth int            // How many subdirectory levels to follow, 0 for none
+	Client   *http.Client   // Used to fetch index pages
+
+	// NewDownloader creates the downloader for one file.
+	NewDownloader func(url, destFile string) *downloader.Downloader
+}
+
+// Stats counts what a mirror run did.
+type Stats struct {
+	Downloaded int
+	Skipped    int
+	Failed     int
+}
+
+// Run walks the index and downloads every matching file. Failed files don't
+// stop the run, their errors are joined in the returned error.
+func (m *Mirror) Run() (Stats, error) {
+	var stats Stats
+	if m.Glob != "" {
+		if _, err := path.Match(m.Glob, ""); err != nil {
+			return stats, fmt.Errorf("bad glob %q: %w", m.Glob, err)
+		}
+	}
+
+	root, err := url.Parse(m.URL)
+	if err != nil {
+		return stats, fmt.Errorf("bad URL %q: %w", m.URL, err)
+	}
+	if !strings.HasSuffix(root.Path, "/") {
+		root.Path += "/" // Directory indexes are resolved relative to a directory
+	}
+
+	var errs []error
+	err = m.walk(root, "", 0, func(e Entry, rel string) {
+		skipped, err := m.mirrorFile(e, rel)
+		switch {
+		case err != nil:
+			log.Printf("Mirror: %s failed: %v", rel, err)
+			errs = append(errs, fmt.Errorf("%s: %w", rel, err))
+			stats.Failed++
+		case skipped:
+			log.Printf("Mirror: %s is up to date, skipping.", rel)
+			stats.Skipped++
+		default:
+			stats.Downloaded++
+		}
+	})
+	if err != nil {
+		return stats, err
+	}
+	return stats, errors.Join(errs...)
+}
+
+// walk lists dir and calls fn for every matching file, recursing into
+// subdirectories up to MaxDepth.
+func (m *Mirror) walk(dir *url.URL, relDir string, depth int, fn func(Entry, string)) error {
+	entries, err := m.list(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	for _, e := range entries {
+		rel := path.Join(relDir, e.Name)
+		if !filepath.IsLocal(filepath.FromSlash(rel)) {
+			log.Printf("Mirror: skipping %s, outside of %s.", e.URL, m.DestDir)
+			continue
+		}
+		if e.Dir {
+			if depth >= m.MaxDepth {
+				continue
+			}
+			sub, err := url.Parse(e.URL)
+			if err != nil {
+				continue
+			}
+			if err := m.walk(sub, rel, depth+1, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if m.match(e.Name, rel) {
+			fn(e, rel)
+		}
+	}
+	return nil
+}
+
+func (m *Mirror) match(name, rel string) bool {
+	if m.Glob != "" {
+		if ok, _ := path.Match(m.Glob, name); !ok {
+			return false
+		}
+	}
+	if m.Regexp != nil && !m.Regexp.MatchString(rel) {
+		return false
+	}
+	return true
+}
+
+// list fetches and parses one index page.
+func (m *Mirror) list(dir *url.URL) ([]Entry, error) {
+	client := m.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(dir.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET returned non-OK status: %s", resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxIndexSize))
+	if err != nil {
+		return nil, err
+	}
+	return ParseIndex(dir, resp.Header.Get("Content-Type"), body)
+}
+
+// mirrorFile downloads one file unless the local copy already matches.
+func (m *Mirror) mirrorFile(e Entry, rel string) (skipped bool, err error) {
+	dest := filepath.Join(m.DestDir, filepath.FromSlash(rel))
+	dl := m.NewDownloader(e.URL, dest)
+
+	plan, err := dl.Plan()
+	if err != nil {
+		return false, err
+	}
+	if upToDate(dest, plan) {
+		return true, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return false, err
+	}
+	if err := dl.RunPlan(plan); err != nil {
+		return false, err
+	}
+	if !plan.LastModified.IsZero() {
+		// Compared by upToDate on the next run
+		if err := os.Chtimes(dest, plan.LastModified, plan.LastModified); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// upToDate reports whether the local file has the remote size and, when the
+// ETag is an MD5 sum, the same content. Otherwise the local modification
+// time must match the remote Last-Modified, when the server sends one.
+func upToDate(dest string, plan *downloader.Plan) bool {
+	stat, err := os.Stat(dest)
+	if err != nil || !stat.Mode().IsRegular() || stat.Size() != plan.FileSize {
+		return false
+	}
+	if plan.Verification != downloader.VerifyMD5 {
+		return plan.LastModified.IsZero() || stat.ModTime().Equal(plan.LastModified)
+	}
+
+	file, err := os.Open(dest)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return false
+	}
+	return strings.EqualFold(hex.EncodeToString(hash.Sum(nil)), plan.ETag)
+}
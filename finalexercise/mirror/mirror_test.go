@@ -0,0 +1,253 @@
+package mirror
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ArditZubaku/parallel-downloader/downloader"
+)
+
+const autoindex = `<html><head><title>Index of /data/</title></head><body>
+<h1>Index of /data/</h1><hr><pre><a href="../">../</a>
+<a href="?C=N;O=D">Name</a>
+<a href="2018-05.parquet">2018-05.parquet</a>     01-Jan-2024 00:00    1000
+<a href="notes%20v1.txt">notes v1.txt</a>        01-Jan-2024 00:00      10
+<a href="sub/">sub/</a>                          01-Jan-2024 00:00       -
+<a href="https://example.com/elsewhere.parquet">elsewhere</a>
+</pre><hr></body></html>`
+
+func TestParseIndexHTML(t *testing.T) {
+	base, _ := url.Parse("http://host/data/")
+	entries, err := ParseIndex(base, "text/html", []byte(autoindex))
+	if err != nil {
+		t.Fatalf("ParseIndex failed: %v", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	expected := "2018-05.parquet,notes v1.txt,sub"
+	if got := strings.Join(names, ","); got != expected {
+		t.Errorf("Expected entries %s, got %s", expected, got)
+	}
+	if !entries[2].Dir || entries[2].URL != "http://host/data/sub/" {
+		t.Errorf("Expected sub/ to be a directory, got %+v", entries[2])
+	}
+}
+
+func TestParseIndexJSON(t *testing.T) {
+	base, _ := url.Parse("http://host/data/")
+	listing := `[
+		{"name": "a.csv", "type": "file", "size": 42},
+		{"name": "nested", "type": "directory"},
+		{"name": "b.csv", "url": "./b.csv", "is_dir": false, "size": 7}
+	]`
+	entries, err := ParseIndex(base, "application/json", []byte(listing))
+	if err != nil {
+		t.Fatalf("ParseIndex failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d: %+v", len(entries), entries)
+	}
+	if entries[0].Size != 42 || !entries[1].Dir || entries[2].URL != "http://host/data/b.csv" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
+
+func TestParseIndexTraversal(t *testing.T) {
+	base, _ := url.Parse("http://host/data/")
+	listing := `<a href="%2e%2e/">up</a>
+<a href="..%2f..%2f/">up twice</a>
+<a href="..%2F..%2Fetc%2Fpasswd">passwd</a>
+<a href="%2e/">self</a>
+<a href="a%5c..%5cb">backslash</a>
+<a href="ok.txt">ok.txt</a>`
+	entries, err := ParseIndex(base, "text/html", []byte(listing))
+	if err != nil {
+		t.Fatalf("ParseIndex failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "ok.txt" {
+		t.Errorf("Expected only ok.txt, got %+v", entries)
+	}
+
+	entries, err = ParseIndex(base, "application/json", []byte(`["..", "../", "a/../../b", "ok.txt"]`))
+	if err != nil {
+		t.Fatalf("ParseIndex failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name != "ok.txt" {
+		t.Errorf("Expected only ok.txt, got %+v", entries)
+	}
+}
+
+func TestMirrorRunMaliciousListing(t *testing.T) {
+	// Every directory lists the hostile links again, and evil.txt is served
+	// anywhere, so a traversal would write outside dest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/") {
+			w.Write([]byte(`<a href="%2e%2e/">up</a> <a href="..%2f..%2f/">up twice</a>
+<a href="..%2f..%2fevil.txt">evil</a> <a href="evil.txt">evil</a>`))
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader("evil"))
+	}))
+	defer server.Close()
+
+	root := t.TempDir()
+	dest := filepath.Join(root, "a", "b", "dest")
+	m := &Mirror{
+		URL:      server.URL + "/data/",
+		DestDir:  dest,
+		MaxDepth: 3,
+		NewDownloader: func(url, destFile string) *downloader.Downloader {
+			return downloader.NewDownloader(url, destFile, 1, 256, 0, 5*time.Second)
+		},
+	}
+	stats, err := m.Run()
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if stats.Downloaded != 1 {
+		t.Errorf("Expected only dest/evil.txt, got %+v", stats)
+	}
+
+	filepath.WalkDir(root, func(p string, de os.DirEntry, err error) error {
+		if err == nil && !de.IsDir() && p != filepath.Join(dest, "evil.txt") {
+			t.Errorf("Mirror wrote %s outside of the listing", p)
+		}
+		return nil
+	})
+}
+
+func TestMirrorRun(t *testing.T) {
+	files := map[string][]byte{
+		"/data/2018-05.parquet":     bytes.Repeat([]byte("p"), 1000),
+		"/data/notes v1.txt":        []byte("0123456789"),
+		"/data/sub/2018-06.parquet": bytes.Repeat([]byte("q"), 300),
+	}
+	var gets, heads atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data/":
+			w.Write([]byte(autoindex))
+		case "/data/sub/":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`[{"name": "2018-06.parquet", "type": "file"}]`))
+		default:
+			content, ok := files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			switch r.Method {
+			case http.MethodGet:
+				gets.Add(1)
+			case http.MethodHead:
+				heads.Add(1)
+			}
+			http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+		}
+	}))
+	defer server.Close()
+
+	dest := t.TempDir()
+	m := &Mirror{
+		URL:      server.URL + "/data/",
+		DestDir:  dest,
+		Regexp:   regexp.MustCompile(`\.parquet$`),
+		MaxDepth: 1,
+		NewDownloader: func(url, destFile string) *downloader.Downloader {
+			return downloader.NewDownloader(url, destFile, 2, 256, 0, 5*time.Second)
+		},
+	}
+
+	stats, err := m.Run()
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if stats.Downloaded != 2 || stats.Skipped != 0 {
+		t.Errorf("Expected 2 downloads, got %+v", stats)
+	}
+	if heads.Load() != 2 {
+		t.Errorf("Expected one HEAD request per file, got %d", heads.Load())
+	}
+	for _, rel := range []string{"2018-05.parquet", "sub/2018-06.parquet"} {
+		data, err := os.ReadFile(filepath.Join(dest, rel))
+		if err != nil {
+			t.Fatalf("Missing mirrored file %s: %v", rel, err)
+		}
+		if !bytes.Equal(data, files["/data/"+rel]) {
+			t.Errorf("Content mismatch for %s", rel)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dest, "notes v1.txt")); !os.IsNotExist(err) {
+		t.Errorf("Filtered file notes v1.txt should not be mirrored")
+	}
+
+	// Second run finds everything up to date
+	before := gets.Load()
+	stats, err = m.Run()
+	if err != nil {
+		t.Fatalf("Second mirror run failed: %v", err)
+	}
+	if stats.Skipped != 2 || gets.Load() != before {
+		t.Errorf("Expected 2 skipped files and no new GETs, got %+v and %d GETs", stats, gets.Load()-before)
+	}
+}
+
+func TestMirrorRunLastModified(t *testing.T) {
+	content := []byte("0123456789")
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var gets atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/data/" {
+			w.Write([]byte(`<a href="a.txt">a.txt</a>`))
+			return
+		}
+		if r.Method == http.MethodGet {
+			gets.Add(1)
+		}
+		w.Header().Set("ETag", `"5f1-abc"`) // Not an MD5 sum
+		http.ServeContent(w, r, "", modTime, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	m := &Mirror{
+		URL:     server.URL + "/data/",
+		DestDir: t.TempDir(),
+		NewDownloader: func(url, destFile string) *downloader.Downloader {
+			return downloader.NewDownloader(url, destFile, 1, 256, 0, 5*time.Second)
+		},
+	}
+	for i, expected := range []Stats{{Downloaded: 1}, {Skipped: 1}} {
+		stats, err := m.Run()
+		if err != nil {
+			t.Fatalf("Mirror run %d failed: %v", i+1, err)
+		}
+		if stats != expected {
+			t.Errorf("Run %d: expected %+v, got %+v", i+1, expected, stats)
+		}
+	}
+
+	// Same size, but changed on the server
+	modTime = modTime.Add(time.Hour)
+	before := gets.Load()
+	stats, err := m.Run()
+	if err != nil {
+		t.Fatalf("Mirror failed: %v", err)
+	}
+	if stats.Downloaded != 1 || gets.Load() == before {
+		t.Errorf("Expected a new download of a.txt, got %+v", stats)
+	}
+}
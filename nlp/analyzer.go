@@ -0,0 +1,128 @@
+package nlp
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/ArditZubaku/nlp/stemmer"
+)
+
+// Analyzer turns text into tokens: it splits the text into words and passes
+// them through a chain of filters, in order.
+type Analyzer struct {
+	// Split splits text into raw tokens, nil means SplitWords
+	Split   func(text string) []Token
+	Filters []TokenFilter
+}
+
+// NewAnalyzer returns an Analyzer splitting words with SplitWords.
+func NewAnalyzer(filters ...TokenFilter) *Analyzer {
+	return &Analyzer{Filters: filters}
+}
+
+// Analyze returns the tokens of text. Tokens left with an empty term are
+// dropped.
+func (a *Analyzer) Analyze(text string) []Token {
+	return a.filter(a.split(text))
+}
+
+func (a *Analyzer) split(text string) []Token {
+	if a.Split == nil {
+		return SplitWords(text)
+	}
+	return a.Split(text)
+}
+
+// filter passes tokens through the filters and drops empty terms.
+func (a *Analyzer) filter(tokens []Token) []Token {
+	for _, f := range a.Filters {
+		tokens = f.Filter(tokens)
+	}
+	return keep(func(tok Token) bool { return tok.Stem != "" }).Filter(tokens)
+}
+
+// Terms returns the terms (Stem field) of the tokens of text.
+func (a *Analyzer) Terms(text string) []string {
+	var terms []string
+	for _, tok := range a.Analyze(text) {
+		terms = append(terms, tok.Stem)
+	}
+	return terms
+}
+
+// SplitWords splits text into words using the Unicode word boundary rules
+// (UAX #29), so accented letters, non-Latin scripts and numbers are kept,
+// and each Chinese or Japanese ideograph is a token of its own.
+// Normalized and Stem are set to the word as is.
+func SplitWords(text string) []Token {
+	var tokens []Token
+	runes, last := 0, 0 // Rune count of text[:last]
+	for _, s := range wordSpans(text) {
+		runes += utf8.RuneCountInString(text[last:s.start])
+		size := utf8.RuneCountInString(text[s.start:s.end])
+		last = s.end
+
+		w := text[s.start:s.end]
+		tokens = append(tokens, Token{
+			Text:       w,
+			Normalized: w,
+			Stem:       w,
+			Start:      s.start,
+			End:        s.end,
+			RuneStart:  runes,
+			RuneEnd:    runes + size,
+			Position:   len(tokens),
+		})
+		runes += size
+	}
+	return tokens
+}
+
+// presets are the named analyzers, functions since filters may keep state
+// and callers may change the filters of the returned Analyzer.
+var presets = map[string]func() *Analyzer{
+	// What Tokenize does
+	"standard": func() *Analyzer {
+		return NewAnalyzer(Lowercase(), StemFilter(stemmer.Func(stemmer.Stem)))
+	},
+	// Whole words, for autocomplete
+	"simple": func() *Analyzer {
+		return NewAnalyzer(Lowercase())
+	},
+	// Without stop words, for search
+	"search": func() *Analyzer {
+		stop, _ := StopWordList("en")
+		return NewAnalyzer(Lowercase(), StopWords(stop...), StemFilter(stemmer.Func(stemmer.Stem)))
+	},
+	// Dictionary words instead of stems, for display
+	"lemma": func() *Analyzer {
+		return NewAnalyzer(Lowercase(), LemmaFilter())
+	},
+	// Accent insensitive, for matching names
+	"match": func() *Analyzer {
+		return NewAnalyzer(Lowercase(), ASCIIFold())
+	},
+}
+
+// Preset returns a new Analyzer for a preset name: "standard" (case folding
+// and English stemming, what Tokenize does), "simple" (case folding only),
+// "search" (like standard, without English stop words), "lemma" (like
+// standard, with English lemmas instead of stems) or "match" (case and
+// accent folding).
+func Preset(name string) (*Analyzer, bool) {
+	preset, ok := presets[name]
+	if !ok {
+		return nil, false
+	}
+	return preset(), true
+}
+
+// Presets returns the preset names, sorted.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
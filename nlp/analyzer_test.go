@@ -0,0 +1,64 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/ArditZubaku/nlp/stemmer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzerFilters(t *testing.T) {
+	a := NewAnalyzer(
+		Lowercase(),
+		ASCIIFold(),
+		StopWords("the", "of"),
+		Synonyms(map[string][]string{"cafe": {"coffee"}}),
+		StemFilter(stemmer.Func(stemmer.Stem)),
+		MinLength(2),
+		MaxLength(6),
+	)
+
+	tokens := a.Analyze("The Café of a Naïve programmer")
+	require.Equal(t, []string{"cafe", "coffe", "naiv"}, a.Terms("The Café of a Naïve programmer"))
+
+	require.Equal(t, "Café", tokens[0].Text)
+	require.Equal(t, "cafe", tokens[0].Normalized)
+	require.Equal(t, 1, tokens[0].Position)
+	// The synonym shares the word position and offsets
+	require.Equal(t, "Café", tokens[1].Text)
+	require.Equal(t, 1, tokens[1].Position)
+	require.Equal(t, 4, tokens[2].Position)
+}
+
+func TestASCIIFold(t *testing.T) {
+	require.Equal(t, "Cafe creme, strasse, AEsop", asciiFold("Café crème, straße, Æsop"))
+	require.Equal(t, "naive", asciiFold("naïve")) // Combining diaeresis
+}
+
+func TestPresets(t *testing.T) {
+	require.Equal(t, []string{"lemma", "match", "search", "simple", "standard"}, Presets())
+
+	text := "The Naïve Programmers"
+	expected := map[string][]string{
+		"standard": {"the", "naïv", "programm"},
+		"simple":   {"the", "naïve", "programmers"},
+		"search":   {"naïv", "programm"},
+		"match":    {"the", "naive", "programmers"},
+	}
+	for name, terms := range expected {
+		a, ok := Preset(name)
+		require.True(t, ok, name)
+		require.Equal(t, terms, a.Terms(text), name)
+	}
+
+	standard, _ := Preset("standard")
+	require.Equal(t, standard.Terms(text), Tokenize(text))
+
+	_, ok := Preset("nope")
+	require.False(t, ok)
+}
+
+func TestTokenizeWithAnalyzer(t *testing.T) {
+	a, _ := Preset("simple")
+	require.Equal(t, []string{"working", "hard"}, Tokenize("Working hard", WithAnalyzer(a)))
+}
@@ -0,0 +1,305 @@
+package nlp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+)
+
+// Example is a text with its class label, for training a Classifier.
+type Example struct {
+	Text  string `json:"text"`
+	Label string `json:"label"`
+}
+
+// ReadExamples reads examples in JSON Lines format, one
+// `{"text": "...", "label": "..."}` object per line. Empty lines are ignored.
+func ReadExamples(r io.Reader) ([]Example, error) {
+	var examples []Example
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 1<<20)
+	for lnum := 1; s.Scan(); lnum++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+
+		var e Example
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			return nil, fmt.Errorf("%d: %w", lnum, err)
+		}
+		if e.Label == "" {
+			return nil, fmt.Errorf("%d: missing label", lnum)
+		}
+		examples = append(examples, e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return examples, nil
+}
+
+// Classifier is a multinomial Naive Bayes text classifier. The features of
+// a text are the counts of its terms from Tokenize, by default with the
+// "search" preset: stop words say little about the class of a text.
+// Probabilities use Laplace (add one) smoothing.
+//
+// A trained Classifier is safe for concurrent use.
+type Classifier struct {
+	opts   []Option
+	labels []string                  // Sorted
+	docs   map[string]int            // Label -> number of examples
+	counts map[string]map[string]int // Label -> term -> count
+	totals map[string]int            // Label -> number of terms
+	vocab  map[string]bool
+}
+
+// Prediction is a class label with its probability.
+type Prediction struct {
+	Label       string  `json:"label"`
+	Probability float64 `json:"probability"`
+}
+
+// NewClassifier returns an untrained classifier, opts are passed to
+// Tokenize. Without options, texts are analyzed with the "search" preset.
+func NewClassifier(opts ...Option) *Classifier {
+	if len(opts) == 0 {
+		a, _ := Preset("search")
+		opts = []Option{WithAnalyzer(a)}
+	}
+	return &Classifier{
+		opts:   opts,
+		docs:   make(map[string]int),
+		counts: make(map[string]map[string]int),
+		totals: make(map[string]int),
+		vocab:  make(map[string]bool),
+	}
+}
+
+// Train adds examples to the model, it can be called several times.
+func (c *Classifier) Train(examples []Example) {
+	a := newOptions(c.opts).newAnalyzer()
+	for _, e := range examples {
+		if _, ok := c.docs[e.Label]; !ok {
+			c.labels = append(c.labels, e.Label)
+			c.counts[e.Label] = make(map[string]int)
+		}
+		c.docs[e.Label]++
+		for _, term := range a.Terms(e.Text) {
+			c.counts[e.Label][term]++
+			c.totals[e.Label]++
+			c.vocab[term] = true
+		}
+	}
+	sort.Strings(c.labels)
+}
+
+// Labels returns the class labels, sorted.
+func (c *Classifier) Labels() []string {
+	return c.labels
+}
+
+// Predict returns the labels of the model with the probability that text is
+// of each, most likely first. Terms not seen in training are ignored, a text
+// without known terms gets the label frequencies of the training examples.
+// It returns nil for an untrained model.
+func (c *Classifier) Predict(text string) []Prediction {
+	if len(c.labels) == 0 {
+		return nil
+	}
+
+	terms := make(map[string]int)
+	for _, term := range newOptions(c.opts).newAnalyzer().Terms(text) {
+		if c.vocab[term] {
+			terms[term]++
+		}
+	}
+
+	numDocs := 0
+	for _, n := range c.docs {
+		numDocs += n
+	}
+	logProbs := make([]float64, len(c.labels))
+	best := math.Inf(-1)
+	for i, label := range c.labels {
+		lp := math.Log(float64(c.docs[label]) / float64(numDocs))
+		denom := float64(c.totals[label] + len(c.vocab))
+		for term, n := range terms {
+			lp += float64(n) * math.Log(float64(c.counts[label][term]+1)/denom)
+		}
+		logProbs[i] = lp
+		best = max(best, lp)
+	}
+
+	// Normalize, shifted by best so exp doesn't underflow
+	total := 0.0
+	for _, lp := range logProbs {
+		total += math.Exp(lp - best)
+	}
+	predictions := make([]Prediction, len(c.labels))
+	for i, label := range c.labels {
+		predictions[i] = Prediction{Label: label, Probability: math.Exp(logProbs[i]-best) / total}
+	}
+	sort.SliceStable(predictions, func(i, j int) bool {
+		return predictions[i].Probability > predictions[j].Probability
+	})
+	return predictions
+}
+
+// Classify returns the most likely label of text, "" for an untrained model.
+func (c *Classifier) Classify(text string) string {
+	if p := c.Predict(text); len(p) > 0 {
+		return p[0].Label
+	}
+	return ""
+}
+
+// classifierJSON is the serialized form of a Classifier.
+type classifierJSON struct {
+	Docs   map[string]int            `json:"docs"`
+	Counts map[string]map[string]int `json:"counts"`
+}
+
+// Save writes the term counts of c as JSON.
+func (c *Classifier) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(classifierJSON{Docs: c.docs, Counts: c.counts})
+}
+
+// LoadClassifier reads a model written by Save. The options are not saved,
+// use the ones of the saved model.
+func LoadClassifier(r io.Reader, opts ...Option) (*Classifier, error) {
+	var data classifierJSON
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	if len(data.Docs) == 0 {
+		return nil, errors.New("bad classifier model: no labels")
+	}
+	if len(data.Docs) != len(data.Counts) {
+		return nil, fmt.Errorf("bad classifier model: %d labels, %d term counts", len(data.Docs), len(data.Counts))
+	}
+
+	c := NewClassifier(opts...)
+	for label, n := range data.Docs {
+		counts, ok := data.Counts[label]
+		if !ok || n <= 0 {
+			return nil, fmt.Errorf("bad classifier model: bad label %q", label)
+		}
+		c.labels = append(c.labels, label)
+		c.docs[label] = n
+		c.counts[label] = counts
+		for term, count := range counts {
+			c.totals[label] += count
+			c.vocab[term] = true
+		}
+	}
+	sort.Strings(c.labels)
+	return c, nil
+}
+
+// Metrics are the results of evaluating a classifier.
+type Metrics struct {
+	Accuracy float64                 `json:"accuracy"`
+	MacroF1  float64                 `json:"macro_f1"` // Mean F1 of the labels
+	Labels   map[string]LabelMetrics `json:"labels"`
+}
+
+// LabelMetrics are the results of a classifier for one label.
+type LabelMetrics struct {
+	Precision float64 `json:"precision"` // Predictions of the label that are right
+	Recall    float64 `json:"recall"`    // Examples of the label predicted right
+	F1        float64 `json:"f1"`        // Harmonic mean of precision and recall
+	Support   int     `json:"support"`   // Number of examples of the label
+}
+
+// Evaluate returns the metrics of c on test examples.
+func (c *Classifier) Evaluate(test []Example) Metrics {
+	results := make([]labelPair, len(test))
+	for i, e := range test {
+		results[i] = labelPair{want: e.Label, got: c.Classify(e.Text)}
+	}
+	return newMetrics(results)
+}
+
+// CrossValidate returns the metrics of k-fold cross-validation over
+// examples: they are shuffled (always the same way) and split in k folds,
+// each fold is classified by a model trained on the others. opts are passed
+// to NewClassifier.
+func CrossValidate(examples []Example, k int, opts ...Option) (Metrics, error) {
+	if k < 2 || k > len(examples) {
+		return Metrics{}, errors.New("need at least 2 folds and one example per fold")
+	}
+
+	shuffled := make([]Example, len(examples))
+	copy(shuffled, examples)
+	rng := rand.New(rand.NewPCG(1, 2))
+	rng.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	var results []labelPair
+	for fold := range k {
+		var train, test []Example
+		for i, e := range shuffled {
+			if i%k == fold {
+				test = append(test, e)
+			} else {
+				train = append(train, e)
+			}
+		}
+
+		c := NewClassifier(opts...)
+		c.Train(train)
+		for _, e := range test {
+			results = append(results, labelPair{want: e.Label, got: c.Classify(e.Text)})
+		}
+	}
+	return newMetrics(results), nil
+}
+
+// labelPair is the label of an example and the label predicted for it.
+type labelPair struct {
+	want, got string
+}
+
+// newMetrics returns the metrics of predictions.
+func newMetrics(results []labelPair) Metrics {
+	var (
+		ok        = 0
+		predicted = make(map[string]int) // Label -> times predicted
+		right     = make(map[string]int) // Label -> times predicted right
+		support   = make(map[string]int)
+	)
+	for _, r := range results {
+		support[r.want]++
+		predicted[r.got]++
+		if r.got == r.want {
+			ok++
+			right[r.got]++
+		}
+	}
+
+	m := Metrics{Labels: make(map[string]LabelMetrics)}
+	if len(results) > 0 {
+		m.Accuracy = float64(ok) / float64(len(results))
+	}
+	for label, n := range support {
+		lm := LabelMetrics{Support: n, Recall: float64(right[label]) / float64(n)}
+		if predicted[label] > 0 {
+			lm.Precision = float64(right[label]) / float64(predicted[label])
+		}
+		if lm.Precision+lm.Recall > 0 {
+			lm.F1 = 2 * lm.Precision * lm.Recall / (lm.Precision + lm.Recall)
+		}
+		m.Labels[label] = lm
+		m.MacroF1 += lm.F1
+	}
+	if len(support) > 0 {
+		m.MacroF1 /= float64(len(support))
+	}
+	return m
+}
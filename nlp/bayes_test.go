@@ -0,0 +1,127 @@
+package nlp
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readExamplesFile(t *testing.T, path string) []Example {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	examples, err := ReadExamples(file)
+	require.NoError(t, err)
+	return examples
+}
+
+func TestReadExamples(t *testing.T) {
+	examples, err := ReadExamples(strings.NewReader(`{"text": "Where is my parcel?", "label": "shipping"}
+
+{"text": "Refund please", "label": "billing"}
+`))
+	require.NoError(t, err)
+	require.Equal(t, []Example{{"Where is my parcel?", "shipping"}, {"Refund please", "billing"}}, examples)
+
+	for _, data := range []string{`{"text": "no label"}`, `{"text": `, `["a", "b"]`} {
+		_, err := ReadExamples(strings.NewReader(data))
+		require.Error(t, err, data)
+	}
+}
+
+func TestClassifier(t *testing.T) {
+	c := NewClassifier()
+	require.Nil(t, c.Predict("anything"))
+	require.Equal(t, "", c.Classify("anything"))
+
+	c.Train(readExamplesFile(t, "testdata/messages.jsonl"))
+	require.Equal(t, []string{"account", "billing", "shipping", "technical"}, c.Labels())
+
+	tests := []struct {
+		text  string
+		label string
+	}{
+		{"I was charged two times, please refund me", "billing"},
+		{"The app shows an error and crashes on startup", "technical"},
+		{"My parcel never arrived, the tracking is stuck", "shipping"},
+		{"I can't log in, the password reset does not work", "account"},
+	}
+	for _, tc := range tests {
+		predictions := c.Predict(tc.text)
+		require.Len(t, predictions, 4)
+		require.Equal(t, tc.label, predictions[0].Label, "%s: %v", tc.text, predictions)
+
+		total := 0.0
+		for i, p := range predictions {
+			total += p.Probability
+			if i > 0 {
+				require.LessOrEqual(t, p.Probability, predictions[i-1].Probability)
+			}
+		}
+		require.InDelta(t, 1, total, 1e-9)
+	}
+
+	// Without known terms, the label frequencies: all the same here
+	for _, p := range c.Predict("zzz qqq") {
+		require.InDelta(t, 0.25, p.Probability, 1e-9)
+	}
+}
+
+func TestClassifierSaveLoad(t *testing.T) {
+	c := NewClassifier()
+	c.Train(readExamplesFile(t, "testdata/messages.jsonl"))
+
+	var buf bytes.Buffer
+	require.NoError(t, c.Save(&buf))
+	loaded, err := LoadClassifier(&buf)
+	require.NoError(t, err)
+	require.Equal(t, c.Labels(), loaded.Labels())
+
+	text := "The invoice is wrong and the delivery is late"
+	want, got := c.Predict(text), loaded.Predict(text)
+	require.Len(t, got, len(want))
+	for i := range want {
+		require.Equal(t, want[i].Label, got[i].Label)
+		require.InDelta(t, want[i].Probability, got[i].Probability, 1e-12)
+	}
+
+	for _, data := range []string{"", `{"docs": {}, "counts": {}}`, `{"docs": {"a": 1}, "counts": {}}`, `{"docs": {"a": 0}, "counts": {"a": {}}}`} {
+		_, err := LoadClassifier(strings.NewReader(data))
+		require.Error(t, err, data)
+	}
+}
+
+func TestCrossValidate(t *testing.T) {
+	examples := readExamplesFile(t, "testdata/messages.jsonl")
+	m, err := CrossValidate(examples, 5)
+	require.NoError(t, err)
+	t.Logf("accuracy: %.3f, macro F1: %.3f", m.Accuracy, m.MacroF1)
+	require.Greater(t, m.Accuracy, 0.7)
+	require.Greater(t, m.MacroF1, 0.7)
+	require.Len(t, m.Labels, 4)
+	for label, lm := range m.Labels {
+		require.Equal(t, 25, lm.Support, label)
+	}
+
+	again, err := CrossValidate(examples, 5)
+	require.NoError(t, err)
+	require.InDelta(t, m.Accuracy, again.Accuracy, 1e-12)
+
+	_, err = CrossValidate(examples, 1)
+	require.Error(t, err)
+	_, err = CrossValidate(examples[:3], 5)
+	require.Error(t, err)
+}
+
+func TestEvaluate(t *testing.T) {
+	c := NewClassifier()
+	c.Train([]Example{{"good great fine", "pos"}, {"bad awful poor", "neg"}})
+	m := c.Evaluate([]Example{{"great", "pos"}, {"awful", "neg"}, {"fine", "neg"}})
+	require.InDelta(t, 2.0/3, m.Accuracy, 1e-9)
+	require.Equal(t, LabelMetrics{Precision: 0.5, Recall: 1, F1: 2.0 / 3, Support: 1}, m.Labels["pos"])
+	require.Equal(t, LabelMetrics{Precision: 1, Recall: 0.5, F1: 2.0 / 3, Support: 2}, m.Labels["neg"])
+}
@@ -0,0 +1,103 @@
+package nlp
+
+import "sort"
+
+// BKTree is a Burkhard-Keller tree: a set of words searched for the words
+// within an edit distance of a query, without comparing the query with all
+// of them. It works with any distance that is a metric, such as Levenshtein
+// or DamerauLevenshtein.
+//
+// A BKTree is not safe for concurrent use while words are added.
+type BKTree struct {
+	dist func(a, b string) int
+	root *bkNode
+	size int
+}
+
+// bkNode is a word of a BKTree, its children are the words at distance
+// d from it under children[d].
+type bkNode struct {
+	word     string
+	children map[int]*bkNode
+}
+
+// Match is a word found by a fuzzy lookup, with its distance to the query.
+type Match struct {
+	Word     string `json:"word"`
+	Distance int    `json:"distance"`
+}
+
+// NewBKTree returns an empty tree using dist, nil means DamerauLevenshtein.
+func NewBKTree(dist func(a, b string) int) *BKTree {
+	if dist == nil {
+		dist = DamerauLevenshtein
+	}
+	return &BKTree{dist: dist}
+}
+
+// Add adds word to the tree and reports whether it was missing.
+func (t *BKTree) Add(word string) bool {
+	if t.root == nil {
+		t.root = &bkNode{word: word}
+		t.size++
+		return true
+	}
+
+	node := t.root
+	for {
+		d := t.dist(word, node.word)
+		if d == 0 {
+			return false
+		}
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{word: word}
+			t.size++
+			return true
+		}
+		node = child
+	}
+}
+
+// Len returns the number of words in the tree.
+func (t *BKTree) Len() int {
+	return t.size
+}
+
+// Search returns the words at most maxDist away from word, closest first,
+// ties in word order.
+func (t *BKTree) Search(word string, maxDist int) []Match {
+	var matches []Match
+	if t.root == nil {
+		return nil
+	}
+
+	// By the triangle inequality, only children at distance d-maxDist to
+	// d+maxDist of a node at distance d can match
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := t.dist(word, node.word)
+		if d <= maxDist {
+			matches = append(matches, Match{Word: node.word, Distance: d})
+		}
+		for cd, child := range node.children {
+			if cd >= d-maxDist && cd <= d+maxDist {
+				stack = append(stack, child)
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance != matches[j].Distance {
+			return matches[i].Distance < matches[j].Distance
+		}
+		return matches[i].Word < matches[j].Word
+	})
+	return matches
+}
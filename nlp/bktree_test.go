@@ -0,0 +1,45 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBKTree(t *testing.T) {
+	tree := NewBKTree(nil)
+	require.Empty(t, tree.Search("book", 2))
+
+	for _, word := range []string{"book", "books", "cake", "boo", "boon", "cook", "cape", "cart"} {
+		require.True(t, tree.Add(word), word)
+	}
+	require.False(t, tree.Add("book"))
+	require.Equal(t, 8, tree.Len())
+
+	require.Equal(t, []Match{{"book", 0}, {"boo", 1}, {"books", 1}, {"boon", 1}, {"cook", 1}}, tree.Search("book", 1))
+	require.Equal(t, []Match{{"cake", 1}, {"cape", 2}, {"cart", 2}, {"cook", 2}}, tree.Search("caek", 2))
+	require.Empty(t, tree.Search("xyz", 1))
+}
+
+// TestBKTreeExhaustive checks searches against comparing with all words.
+func TestBKTreeExhaustive(t *testing.T) {
+	words := Tokenize("It was the best of times, it was the worst of times, it was the age of wisdom, it was the age of foolishness", WithAnalyzer(NewAnalyzer(Lowercase())))
+	tree := NewBKTree(Levenshtein)
+	for _, w := range words {
+		tree.Add(w)
+	}
+
+	for _, query := range []string{"time", "wisdon", "fool", "age", "wst"} {
+		for maxDist := range 4 {
+			var want []Match
+			seen := make(map[string]bool)
+			for _, w := range words {
+				if d := Levenshtein(query, w); d <= maxDist && !seen[w] {
+					seen[w] = true
+					want = append(want, Match{w, d})
+				}
+			}
+			require.ElementsMatch(t, want, tree.Search(query, maxDist), "%s %d", query, maxDist)
+		}
+	}
+}
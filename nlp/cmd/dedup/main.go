@@ -0,0 +1,91 @@
+// Command dedup prints the clusters of near-duplicate files in a directory.
+//
+//	dedup -threshold 0.8 docs/
+//	dedup -method simhash -distance 10 docs/
+//
+// Files are read recursively, skipping hidden files and directories and
+// files that are not UTF-8 text. Each cluster is printed as its file paths,
+// one per line, followed by an empty line.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ArditZubaku/nlp"
+)
+
+// index is a near-duplicate index, nlp.MinHashIndex or nlp.SimHashIndex.
+type index interface {
+	Add(id, text string) bool
+	Clusters() [][]string
+}
+
+func main() {
+	method := flag.String("method", "minhash", "detection method: minhash or simhash")
+	threshold := flag.Float64("threshold", 0.8, "minimal similarity of minhash duplicates, from 0 to 1")
+	distance := flag.Int("distance", 10, "maximal fingerprint distance of simhash duplicates, in bits")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [options] DIR\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var idx index
+	switch *method {
+	case "minhash":
+		idx = nlp.NewMinHashIndex(*threshold)
+	case "simhash":
+		idx = nlp.NewSimHashIndex(*distance)
+	default:
+		log.Fatalf("ERROR: unknown method %q (supported: minhash, simhash)", *method)
+	}
+
+	if err := addFiles(idx, flag.Arg(0)); err != nil {
+		log.Fatalf("ERROR: %s", err)
+	}
+	for _, cluster := range idx.Clusters() {
+		for _, path := range cluster {
+			fmt.Println(path)
+		}
+		fmt.Println()
+	}
+}
+
+// addFiles adds the text files under dir to idx, by path.
+func addFiles(idx index, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !utf8.Valid(data) {
+			return nil
+		}
+		idx.Add(path, string(data))
+		return nil
+	})
+}
@@ -0,0 +1,92 @@
+// Command nbtrain trains a Naive Bayes classifier on labeled examples, in
+// JSON Lines format, and saves the model for nlpd (see NLPD_CLASSIFIER).
+//
+//	nbtrain -o model.json -folds 5 messages.jsonl
+//
+// It prints the cross-validation metrics of the examples before training
+// on all of them.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/ArditZubaku/nlp"
+)
+
+func main() {
+	out := flag.String("o", "model.json", "model file")
+	folds := flag.Int("folds", 5, "cross-validation folds, 0 to skip")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [options] EXAMPLES.jsonl\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	examples, err := readExamples(flag.Arg(0))
+	if err != nil {
+		log.Fatalf("ERROR: %s", err)
+	}
+
+	if *folds > 0 {
+		m, err := nlp.CrossValidate(examples, *folds)
+		if err != nil {
+			log.Fatalf("ERROR: %s", err)
+		}
+		printMetrics(m)
+	}
+
+	c := nlp.NewClassifier()
+	c.Train(examples)
+	if err := saveClassifier(c, *out); err != nil {
+		log.Fatalf("ERROR: %s", err)
+	}
+}
+
+func readExamples(path string) ([]nlp.Example, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	examples, err := nlp.ReadExamples(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%w", path, err)
+	}
+	return examples, nil
+}
+
+func printMetrics(m nlp.Metrics) {
+	labels := make([]string, 0, len(m.Labels))
+	for label := range m.Labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	fmt.Printf("%-15s %9s %9s %9s %9s\n", "label", "precision", "recall", "f1", "support")
+	for _, label := range labels {
+		lm := m.Labels[label]
+		fmt.Printf("%-15s %9.3f %9.3f %9.3f %9d\n", label, lm.Precision, lm.Recall, lm.F1, lm.Support)
+	}
+	fmt.Printf("\naccuracy: %.3f, macro F1: %.3f\n", m.Accuracy, m.MacroF1)
+}
+
+func saveClassifier(c *nlp.Classifier, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := c.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
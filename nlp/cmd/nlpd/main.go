@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"expvar"
 	"fmt"
@@ -8,20 +9,68 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/ArditZubaku/nlp"
 	"github.com/ArditZubaku/nlp/stemmer"
 	"github.com/gorilla/mux"
 )
 
+// maxUpload is the maximal size of a /tokenize body.
+const maxUpload = 1 << 30
+
+// defaultHits is the number of /search hits without ?n.
+const defaultHits = 10
+
+// defaultSuggestions is the number of /suggest suggestions without ?n.
+const defaultSuggestions = 5
+
+// defaultKeywords is the number of /keywords keyphrases without ?limit.
+const defaultKeywords = 10
+
+// defaultSummary is the number of /summarize sentences without ?n.
+const defaultSummary = 3
+
+// detectBytes is how much of a /tokenize body is used to detect its language.
+const detectBytes = 4096
+
+// minDetectConfidence is the confidence needed to analyze a text without
+// ?lang in its detected language, otherwise it is analyzed as English.
+const minDetectConfidence = 0.5
+
 var (
-	numTok = expvar.NewInt("tokenize.calls")
+	numTok    = expvar.NewInt("tokenize.calls")
+	numSent   = expvar.NewInt("sentences.calls")
+	numIndex  = expvar.NewInt("index.calls")
+	numSearch = expvar.NewInt("search.calls")
+	numTag    = expvar.NewInt("tag.calls")
+	numLang   = expvar.NewInt("language.calls")
+	numSugg   = expvar.NewInt("suggest.calls")
+	numKeys   = expvar.NewInt("keywords.calls")
+	numSumm   = expvar.NewInt("summarize.calls")
+	numClass  = expvar.NewInt("classify.calls")
+	numPolar  = expvar.NewInt("sentiment.calls")
 )
 
 func main() {
 	// Create server (dependency injection)
 	logger := log.New(log.Writer(), "nlp ", log.LstdFlags|log.Lshortfile)
-	s := Server{logger} // dependency injection - build all the components and then build the server
+	// The index is saved to NLPD_INDEX on every change, if set
+	indexFile := os.Getenv("NLPD_INDEX")
+	index, err := openIndex(indexFile)
+	if err != nil {
+		log.Fatalf("ERROR: %s", err)
+	}
+	// The classifier model is loaded from NLPD_CLASSIFIER, if set
+	classifier, err := openClassifier(os.Getenv("NLPD_CLASSIFIER"))
+	if err != nil {
+		log.Fatalf("ERROR: %s", err)
+	}
+	s := Server{logger: logger, index: index, indexFile: indexFile, classifier: classifier} // dependency injection - build all the components and then build the server
 	// Routing
 	// `/health` is an exact match
 	// `/health/` is a prefix match
@@ -31,6 +80,19 @@ func main() {
 	r.HandleFunc("/health", s.healthHandler).Methods(http.MethodGet)
 	r.HandleFunc("/tokenize", s.tokenizeHandler).Methods(http.MethodPost)
 	r.HandleFunc("/stem/{word}", s.stemHandler).Methods(http.MethodGet)
+	r.HandleFunc("/sentences", s.sentencesHandler).Methods(http.MethodPost)
+	r.HandleFunc("/tag", s.tagHandler).Methods(http.MethodPost)
+	r.HandleFunc("/language", s.languageHandler).Methods(http.MethodPost)
+	r.HandleFunc("/keywords", s.keywordsHandler).Methods(http.MethodPost)
+	r.HandleFunc("/summarize", s.summarizeHandler).Methods(http.MethodPost)
+	r.HandleFunc("/sentiment", s.sentimentHandler).Methods(http.MethodPost)
+	r.HandleFunc("/classify", s.classifyHandler).Methods(http.MethodPost)
+	r.HandleFunc("/classifier", s.classifierHandler).Methods(http.MethodGet)
+	r.HandleFunc("/index", s.indexStatsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/index/{id}", s.indexHandler).Methods(http.MethodPut, http.MethodPost)
+	r.HandleFunc("/index/{id}", s.unindexHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/search", s.searchHandler).Methods(http.MethodGet)
+	r.HandleFunc("/suggest/{word}", s.suggestHandler).Methods(http.MethodGet)
 
 	http.Handle("/", r)
 
@@ -46,13 +108,155 @@ func main() {
 }
 
 type Server struct {
-	logger *log.Logger
+	logger    *log.Logger
+	index     *nlp.Index
+	indexFile string       // Where to save index, "" for no saving
+	changes   atomic.Int64 // Number of index changes
+	saveMu    sync.Mutex   // Serializes saving index, guards saved
+	saved     int64        // Number of index changes in indexFile
+
+	classifier *nlp.Classifier // nil if no model is loaded
+}
+
+// openIndex loads the index saved in path, or returns an empty one if path
+// is "" or doesn't exist yet.
+func openIndex(path string) (*nlp.Index, error) {
+	if path == "" {
+		return nlp.NewIndex(nil), nil
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nlp.NewIndex(nil), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	index, err := nlp.LoadIndex(file, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return index, nil
+}
+
+// openClassifier loads the classifier model saved in path, or returns nil if
+// path is "".
+func openClassifier(path string) (*nlp.Classifier, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	c, err := nlp.LoadClassifier(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// language returns the language code and stemmer for the "lang" query
+// parameter. Without it, the language is detected from sample ("en" if
+// sample is "" or detection fails), and the stemmer is nil if there is none
+// for the detected language.
+// On an unknown language it writes an error and returns false.
+func (s *Server) language(w http.ResponseWriter, r *http.Request, sample string) (string, stemmer.Stemmer, bool) {
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = detect(sample)
+		stm, _ := stemmer.ForLanguage(lang)
+		return lang, stm, true
+	}
+
+	stm, ok := stemmer.ForLanguage(lang)
+	if !ok {
+		msg := fmt.Sprintf("Unknown language %q (supported: %s)", lang, strings.Join(stemmer.Languages(), ", "))
+		http.Error(w, msg, http.StatusBadRequest)
+		return "", nil, false
+	}
+	return lang, stm, true
+}
+
+// detect returns the language of text, or "en" if it can't be told.
+func detect(text string) string {
+	if text == "" {
+		return "en"
+	}
+	guesses := nlp.DetectLanguage(text)
+	if len(guesses) == 0 || guesses[0].Confidence < minDetectConfidence {
+		return "en"
+	}
+	return guesses[0].Lang
+}
+
+// analyzer returns the analyzer for the "lang", "stopwords" and "lemmas"
+// query parameters, ?stopwords=en drops English stop words before stemming,
+// ?stopwords=auto drops those of the language, ?lemmas=true returns English
+// lemmas ("mice" -> "mouse") instead of stems. Without ?lang the language is
+// detected from sample, see language. The language is set as the
+// Content-Language of the response.
+// On bad parameters it writes an error and returns false.
+func (s *Server) analyzer(w http.ResponseWriter, r *http.Request, sample string) (*nlp.Analyzer, bool) {
+	lemmas := false
+	if v := r.URL.Query().Get("lemmas"); v != "" {
+		var err error
+		if lemmas, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Bad lemmas value", http.StatusBadRequest)
+			return nil, false
+		}
+	}
+	if lang := r.URL.Query().Get("lang"); lemmas && lang != "" && lang != "en" {
+		http.Error(w, "Lemmas are only supported for English", http.StatusBadRequest)
+		return nil, false
+	}
+	if lemmas {
+		sample = "" // English
+	}
+
+	lang, stm, ok := s.language(w, r, sample)
+	if !ok {
+		return nil, false
+	}
+	w.Header().Set("Content-Language", lang)
+
+	a := nlp.NewAnalyzer(nlp.Lowercase())
+	if r.URL.Query().Get("stopwords") == "auto" {
+		if words, ok := nlp.StopWordList(lang); ok {
+			a.Filters = append(a.Filters, nlp.StopWords(words...))
+		}
+	} else if list := r.URL.Query().Get("stopwords"); list != "" {
+		words, ok := nlp.StopWordList(list)
+		if !ok {
+			msg := fmt.Sprintf("Unknown stop words language %q (supported: %s)", list, strings.Join(nlp.StopWordLanguages(), ", "))
+			http.Error(w, msg, http.StatusBadRequest)
+			return nil, false
+		}
+		a.Filters = append(a.Filters, nlp.StopWords(words...))
+	}
+	switch {
+	case lemmas:
+		a.Filters = append(a.Filters, nlp.LemmaFilter())
+	case stm != nil:
+		a.Filters = append(a.Filters, nlp.StemFilter(stm))
+	}
+	return a, true
 }
 
 func (s *Server) stemHandler(w http.ResponseWriter, r *http.Request) {
+	_, stm, ok := s.language(w, r, "")
+	if !ok {
+		return
+	}
+
 	vars := mux.Vars(r)
 	word := vars["word"]
-	stem := stemmer.Stem(word)
+	stem := stm.Stem(word)
 	fmt.Fprintln(w, stem)
 }
 
@@ -69,42 +273,467 @@ func (s *Server) tokenizeHandler(w http.ResponseWriter, r *http.Request) {
 
 	numTok.Add(1)
 
-	// Step 1: Get, convert & validate data
-	// Reads only 1MB of memory
-	rdr := io.LimitReader(r.Body, 1_000_000)
-	data, err := io.ReadAll(rdr)
+	// Step 1: Get & validate data
+	// The body is tokenized as it is read, so large uploads use constant memory
+	body := bufio.NewReader(http.MaxBytesReader(w, r.Body, maxUpload))
+	sample, err := body.Peek(detectBytes) // To detect the language
+	if len(sample) == 0 {
+		if err != io.EOF {
+			s.logger.Printf("ERROR: Can't read - %s", err)
+		}
+		http.Error(w, "Missing data", http.StatusBadRequest)
+		return
+	}
+
+	a, ok := s.analyzer(w, r, string(sample))
+	if !ok {
+		return
+	}
+
+	// ?detailed=true returns tokens with their offsets instead of bare stems
+	detailed := false
+	if v := r.URL.Query().Get("detailed"); v != "" {
+		var err error
+		if detailed, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Bad detailed value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Step 2 & 3: Work, encode & emit output as a JSON stream
+	// `{"tokens": [...]}`, with an "error" field if reading fails midway
+	w.Header().Set("Content-Type", "application/json")
+	out := bufio.NewWriter(w)
+	defer out.Flush()
+
+	out.WriteString(`{"tokens":[`)
+	tok := nlp.NewTokenizer(body, nlp.WithAnalyzer(a))
+	i := 0
+	for t := range tok.Tokens() {
+		var item any = t.Stem
+		if detailed {
+			item = t
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			break // Can't happen for strings and tokens
+		}
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		out.Write(data)
+		i++
+	}
+	out.WriteString("]")
+
+	if err := tok.Err(); err != nil {
+		s.logger.Printf("ERROR: Can't read - %s", err)
+		data, _ := json.Marshal(err.Error())
+		fmt.Fprintf(out, `,"error":%s`, data)
+	}
+	out.WriteString("}")
+}
+
+// readText reads the request body text, up to 1MB.
+// On error it writes an error and returns false.
+func (s *Server) readText(w http.ResponseWriter, r *http.Request) (string, bool) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, 1_000_000))
 	if err != nil {
 		s.logger.Printf("ERROR: Can't read - %s", err)
 		http.Error(w, "Can't read", http.StatusBadRequest)
-		// ALWAYS return
-		return
+		return "", false
 	}
 
 	if len(data) == 0 {
 		http.Error(w, "Missing data", http.StatusBadRequest)
+		return "", false
+	}
+	return string(data), true
+}
+
+// writeJSON writes resp as JSON.
+func (s *Server) writeJSON(w http.ResponseWriter, resp any) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, "Can't encode", http.StatusInternalServerError)
 		return
 	}
 
-	// data is a byte slice
-	text := string(data)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
 
-	// Step 2: Work
-	tokens := nlp.Tokenize(text)
+// sentencesHandler splits the request body into sentences, returning JSON in
+// the format `{ "sentences": [{"text": "Who's on first?", "start": 0, ...}] }`
+func (s *Server) sentencesHandler(w http.ResponseWriter, r *http.Request) {
+	numSent.Add(1)
 
-	// Step 3: Encode & Emit output
-	resp := map[string]any{"tokens": tokens}
+	// ?lines=true ends a sentence at every newline, not only at blank lines
+	split := nlp.Sentences
+	if v := r.URL.Query().Get("lines"); v != "" {
+		lines, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "Bad lines value", http.StatusBadRequest)
+			return
+		}
+		if lines {
+			split = nlp.SentencesByLine
+		}
+	}
+
+	text, ok := s.readText(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeJSON(w, map[string]any{"sentences": split(text)})
+}
+
+// tagHandler tags the words of the request body with their part of speech,
+// returning JSON in the format `{ "tokens": [{"text": "Who", "pos": "PRON", ...}] }`
+// ?pos=NOUN,VERB keeps only nouns and verbs. It takes the parameters of
+// /tokenize, stop words are removed after tagging. Each sentence is tagged
+// on its own.
+func (s *Server) tagHandler(w http.ResponseWriter, r *http.Request) {
+	numTag.Add(1)
+
+	if lang := r.URL.Query().Get("lang"); lang != "" && lang != "en" {
+		http.Error(w, "Tagging is only supported for English", http.StatusBadRequest)
+		return
+	}
+	a, ok := s.analyzer(w, r, "")
+	if !ok {
+		return
+	}
+
+	text, ok := s.readText(w, r)
+	if !ok {
+		return
+	}
+
+	a.Filters = append([]nlp.TokenFilter{tagSentences(nlp.Sentences(text))}, a.Filters...)
+	if v := r.URL.Query().Get("pos"); v != "" {
+		a.Filters = append(a.Filters, nlp.KeepPOS(strings.Split(strings.ToUpper(v), ",")...))
+	}
+	tokens := a.Analyze(text)
+	if tokens == nil {
+		tokens = []nlp.Token{} // [] in JSON, not null
+	}
+	s.writeJSON(w, map[string]any{"tokens": tokens})
+}
+
+// tagSentences returns a filter tagging the tokens of each of sentences
+// separately, see nlp.Tag.
+func tagSentences(sentences []nlp.Sentence) nlp.TokenFilter {
+	return nlp.FilterFunc(func(tokens []nlp.Token) []nlp.Token {
+		i := 0
+		for _, sent := range sentences {
+			j := i
+			for j < len(tokens) && tokens[j].Start < sent.End {
+				j++
+			}
+			nlp.Tag(tokens[i:j])
+			i = j
+		}
+		return tokens
+	})
+}
 
-	// We could also do:
-	// err = json.NewEncoder(w).Encode(resp)
-	data, err = json.Marshal(resp)
+// languageHandler detects the language of the request body, returning JSON
+// in the format `{ "languages": [{"lang": "en", "confidence": 0.98}, ...] }`
+// with the most likely first, or no languages if the text is too short.
+func (s *Server) languageHandler(w http.ResponseWriter, r *http.Request) {
+	numLang.Add(1)
+
+	text, ok := s.readText(w, r)
+	if !ok {
+		return
+	}
+
+	guesses := nlp.DetectLanguage(text)
+	if guesses == nil {
+		guesses = []nlp.LanguageGuess{} // [] in JSON, not null
+	}
+	s.writeJSON(w, map[string]any{"languages": guesses})
+}
+
+// keywordsHandler extracts the keyphrases of the request body, returning the
+// ?limit (default 10) best as JSON in the format
+// `{ "keywords": [{"phrase": "orange pips", "score": 6.2, "count": 4}] }`
+// ?method=textrank (the default) works on English, ?method=rake on any
+// language with stop words, given by ?lang or detected.
+func (s *Server) keywordsHandler(w http.ResponseWriter, r *http.Request) {
+	numKeys.Add(1)
+
+	limit := defaultKeywords
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			http.Error(w, "Bad limit value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	method := r.URL.Query().Get("method")
+	lang := r.URL.Query().Get("lang")
+	switch method {
+	case "", "textrank":
+		if lang != "" && lang != "en" {
+			http.Error(w, "TextRank is only supported for English", http.StatusBadRequest)
+			return
+		}
+	case "rake":
+	default:
+		http.Error(w, fmt.Sprintf("Unknown method %q (supported: textrank, rake)", method), http.StatusBadRequest)
+		return
+	}
+
+	text, ok := s.readText(w, r)
+	if !ok {
+		return
+	}
+
+	var keywords []nlp.Keyphrase
+	if method == "rake" {
+		if lang == "" {
+			lang = detect(text)
+		}
+		words, ok := nlp.StopWordList(lang)
+		if !ok {
+			msg := fmt.Sprintf("No stop words for language %q (supported: %s)", lang, strings.Join(nlp.StopWordLanguages(), ", "))
+			http.Error(w, msg, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Language", lang)
+		keywords = nlp.RAKE(text, words, limit)
+	} else {
+		keywords = nlp.TextRank(text, limit)
+	}
+	if keywords == nil {
+		keywords = []nlp.Keyphrase{} // [] in JSON, not null
+	}
+	s.writeJSON(w, map[string]any{"keywords": keywords})
+}
+
+// summarizeHandler returns the ?n (default 3) most central sentences of the
+// request body, in text order, as JSON in the format of /sentences
+// `{ "sentences": [{"text": "Who's on first?", "start": 0, ...}] }`
+func (s *Server) summarizeHandler(w http.ResponseWriter, r *http.Request) {
+	numSumm.Add(1)
+
+	n := defaultSummary
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n <= 0 {
+			http.Error(w, "Bad n value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	text, ok := s.readText(w, r)
+	if !ok {
+		return
+	}
+
+	summary := nlp.Summarize(text, n)
+	if summary == nil {
+		summary = []nlp.Sentence{} // [] in JSON, not null
+	}
+	s.writeJSON(w, map[string]any{"sentences": summary})
+}
+
+// sentimentHandler scores the sentiment of the English request body,
+// returning JSON in the format
+// `{ "pos": 0.49, "neg": 0, "neu": 0.51, "compound": 0.44 }`
+// where compound is the overall polarity from -1 to 1.
+func (s *Server) sentimentHandler(w http.ResponseWriter, r *http.Request) {
+	numPolar.Add(1)
+
+	if lang := r.URL.Query().Get("lang"); lang != "" && lang != "en" {
+		http.Error(w, "Sentiment is only supported for English", http.StatusBadRequest)
+		return
+	}
+	text, ok := s.readText(w, r)
+	if !ok {
+		return
+	}
+
+	s.writeJSON(w, nlp.Polarity(text))
+}
+
+// classifyHandler classifies the request body with the loaded model,
+// returning JSON in the format
+// `{ "label": "billing", "predictions": [{"label": "billing", "probability": 0.93}, ...] }`
+// with the most likely label first.
+func (s *Server) classifyHandler(w http.ResponseWriter, r *http.Request) {
+	numClass.Add(1)
+
+	if !s.hasClassifier(w) {
+		return
+	}
+	text, ok := s.readText(w, r)
+	if !ok {
+		return
+	}
+
+	predictions := s.classifier.Predict(text) // Not empty, LoadClassifier checks there are labels
+	s.writeJSON(w, map[string]any{"label": predictions[0].Label, "predictions": predictions})
+}
+
+// classifierHandler returns the labels of the loaded model as JSON in the
+// format `{ "labels": ["account", "billing"] }`
+func (s *Server) classifierHandler(w http.ResponseWriter, r *http.Request) {
+	if !s.hasClassifier(w) {
+		return
+	}
+	s.writeJSON(w, map[string]any{"labels": s.classifier.Labels()})
+}
+
+// hasClassifier reports whether a classifier model is loaded.
+// If not, it writes an error and returns false.
+func (s *Server) hasClassifier(w http.ResponseWriter) bool {
+	if s.classifier == nil {
+		http.Error(w, "No classifier loaded (set NLPD_CLASSIFIER)", http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+// indexHandler adds or replaces document {id} with the request body text,
+// returning JSON in the format `{ "id": "a1", "created": true, "documents": 1 }`
+func (s *Server) indexHandler(w http.ResponseWriter, r *http.Request) {
+	numIndex.Add(1)
+
+	text, ok := s.readText(w, r)
+	if !ok {
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	created := s.index.Add(id, text)
+	if !s.saveIndex(w) {
+		return
+	}
+
+	s.writeJSON(w, map[string]any{"id": id, "created": created, "documents": s.index.Len()})
+}
+
+// unindexHandler removes document {id}.
+func (s *Server) unindexHandler(w http.ResponseWriter, r *http.Request) {
+	numIndex.Add(1)
+
+	id := mux.Vars(r)["id"]
+	if !s.index.Remove(id) {
+		http.Error(w, fmt.Sprintf("Unknown document %q", id), http.StatusNotFound)
+		return
+	}
+	if !s.saveIndex(w) {
+		return
+	}
+
+	s.writeJSON(w, map[string]any{"id": id, "documents": s.index.Len()})
+}
+
+// indexStatsHandler returns JSON in the format `{ "documents": 3, "terms": 42 }`
+func (s *Server) indexStatsHandler(w http.ResponseWriter, r *http.Request) {
+	s.writeJSON(w, map[string]any{"documents": s.index.Len(), "terms": s.index.Terms()})
+}
+
+// saveIndex saves the index to s.indexFile, if set, after a change. The file
+// is replaced atomically so a crash never leaves a partial index. Changes
+// made while another request saves are saved together by the next request,
+// so concurrent changes don't rewrite the file once each.
+// On error it writes an error and returns false.
+func (s *Server) saveIndex(w http.ResponseWriter) bool {
+	change := s.changes.Add(1)
+	if s.indexFile == "" {
+		return true
+	}
+
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	if s.saved >= change {
+		return true // Saved by another request
+	}
+	changes := s.changes.Load() // Made before writing, so in the file
+	if err := writeIndex(s.index, s.indexFile); err != nil {
+		s.logger.Printf("ERROR: Can't save index - %s", err)
+		http.Error(w, "Can't save index", http.StatusInternalServerError)
+		return false
+	}
+	s.saved = changes
+	return true
+}
+
+func writeIndex(index *nlp.Index, path string) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
 	if err != nil {
-		http.Error(w, "Can't encode", http.StatusInternalServerError)
-		// ALWAYS return
+		return err
+	}
+	defer os.Remove(file.Name()) // No-op after the rename
+
+	if err := index.Save(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// searchHandler searches the index for ?q, returning the ?n (default 10)
+// best documents as JSON in the format `{ "hits": [{"id": "a1", "score": 1.2}] }`
+func (s *Server) searchHandler(w http.ResponseWriter, r *http.Request) {
+	numSearch.Add(1)
+
+	n := defaultHits
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n <= 0 {
+			http.Error(w, "Bad n value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	hits, err := s.index.Search(r.URL.Query().Get("q"), n)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Bad query - %s", err), http.StatusBadRequest)
 		return
 	}
+	if hits == nil {
+		hits = []nlp.Hit{} // [] in JSON, not null
+	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(data)
+	s.writeJSON(w, map[string]any{"hits": hits})
+}
+
+// suggestHandler returns spelling corrections of the English {word}, the ?n
+// (default 5, at most 10) best as JSON in the format
+// `{ "word": "teh", "suggestions": [{"word": "the", "distance": 1, "count": 5620}] }`
+// A correctly spelled word is its own first suggestion.
+func (s *Server) suggestHandler(w http.ResponseWriter, r *http.Request) {
+	numSugg.Add(1)
+
+	n := defaultSuggestions
+	if v := r.URL.Query().Get("n"); v != "" {
+		var err error
+		if n, err = strconv.Atoi(v); err != nil || n <= 0 {
+			http.Error(w, "Bad n value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	word := mux.Vars(r)["word"]
+	suggestions := nlp.Suggest(word)
+	if len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	if suggestions == nil {
+		suggestions = []nlp.Suggestion{} // [] in JSON, not null
+	}
+	s.writeJSON(w, map[string]any{"word": word, "suggestions": suggestions})
 }
 
 func (s *Server) healthHandler(w http.ResponseWriter, r *http.Request) {
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/ArditZubaku/nlp"
+	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/require"
 )
 
@@ -13,7 +22,7 @@ func TestHealth(t *testing.T) {
 	w := httptest.NewRecorder()
 	r := httptest.NewRequest(http.MethodGet, "/health", nil)
 
-	s := Server{log.Default()}
+	s := Server{logger: log.Default()}
 	// NOTE: This bypasses routing and middleware
 	s.healthHandler(w, r)
 
@@ -21,3 +30,545 @@ func TestHealth(t *testing.T) {
 	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
 
 }
+
+func TestTokenizeLanguage(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tokenize?lang=de", strings.NewReader("Die Käufer"))
+	s.tokenizeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Tokens []string
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Equal(t, []string{"die", "kauf"}, reply.Tokens)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/tokenize?lang=xx", strings.NewReader("Die Käufer"))
+	s.tokenizeHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, "Result status")
+}
+
+func TestStemLanguage(t *testing.T) {
+	s := Server{logger: log.Default()}
+	r := mux.NewRouter()
+	r.HandleFunc("/stem/{word}", s.stemHandler)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stem/acciones?lang=es", nil))
+	require.Equal(t, http.StatusOK, w.Code, "Result status")
+	require.Equal(t, "accion\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stem/working", nil))
+	require.Equal(t, "work\n", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stem/acciones?lang=xx", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code, "Result status")
+}
+
+func TestTokenizeDetailed(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tokenize?detailed=true", strings.NewReader("Who's on first?"))
+	s.tokenizeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Tokens []nlp.Token
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Len(t, reply.Tokens, 3)
+	require.Equal(t, nlp.Token{Text: "first", Normalized: "first", Stem: "first", Start: 9, End: 14, RuneStart: 9, RuneEnd: 14, Position: 2}, reply.Tokens[2])
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/tokenize?detailed=maybe", strings.NewReader("Who's on first?"))
+	s.tokenizeHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, "Result status")
+}
+
+func TestTokenizeStopWords(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tokenize?stopwords=en", strings.NewReader("The Adventure of Sherlock Holmes"))
+	s.tokenizeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Tokens []string
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Equal(t, []string{"adventur", "sherlock", "holm"}, reply.Tokens)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/tokenize?stopwords=xx", strings.NewReader("The Adventure"))
+	s.tokenizeHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, "Result status")
+}
+
+func TestTokenizeLemmas(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tokenize?lemmas=true", strings.NewReader("The mice went running"))
+	s.tokenizeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Tokens []string
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Equal(t, []string{"the", "mouse", "go", "run"}, reply.Tokens)
+
+	for _, query := range []string{"lemmas=maybe", "lemmas=true&lang=de"} {
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodPost, "/tokenize?"+query, strings.NewReader("The mice"))
+		s.tokenizeHandler(w, r)
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+	}
+}
+
+func TestTokenizeDetect(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	tests := []struct {
+		query, text, lang string
+		tokens            []string
+	}{
+		{"", "Die Käufer warten vor dem Laden", "de", []string{"die", "kauf", "wart", "vor", "dem", "lad"}},
+		{"?stopwords=auto", "Die Käufer warten vor dem Laden", "de", []string{"kauf", "wart", "lad"}},
+		{"", "Дети читали книги", "ru", []string{"дети", "читали", "книги"}}, // No Russian stemmer
+		{"", "Who's on first?", "en", []string{"who", "on", "first"}},
+		{"?lang=en", "Die Käufer", "en", []string{"die", "käufer"}},
+	}
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/tokenize"+tc.query, strings.NewReader(tc.text))
+		s.tokenizeHandler(w, r)
+
+		resp := w.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode, tc.text)
+		require.Equal(t, tc.lang, resp.Header.Get("Content-Language"), tc.text)
+		var reply struct {
+			Tokens []string
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+		require.Equal(t, tc.tokens, reply.Tokens, tc.text)
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/language", strings.NewReader("Le chien dort devant la maison"))
+	s.languageHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Languages []nlp.LanguageGuess
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.NotEmpty(t, reply.Languages)
+	require.Equal(t, "fr", reply.Languages[0].Lang)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/language", strings.NewReader("42"))
+	s.languageHandler(w, r)
+	require.Equal(t, http.StatusOK, w.Code, "Result status")
+	require.JSONEq(t, `{"languages":[]}`, w.Body.String())
+}
+
+func TestTag(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tag", strings.NewReader("The old man walked slowly"))
+	s.tagHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Tokens []nlp.Token
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	var tags []string
+	for _, tok := range reply.Tokens {
+		tags = append(tags, tok.POS)
+	}
+	require.Equal(t, []string{"DET", "ADJ", "NOUN", "VERB", "ADV"}, tags)
+	require.Equal(t, "walk", reply.Tokens[3].Stem)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/tag?pos=noun,verb&lemmas=true", strings.NewReader("The old man walked slowly"))
+	s.tagHandler(w, r)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&reply))
+	require.Len(t, reply.Tokens, 2)
+	require.Equal(t, "man", reply.Tokens[0].Stem)
+	require.Equal(t, "walk", reply.Tokens[1].Stem)
+
+	// Sentences are tagged separately, "Stay" starts one
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/tag?pos=verb", strings.NewReader("Not a bit, Doctor. Stay where you are."))
+	s.tagHandler(w, r)
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&reply))
+	require.Len(t, reply.Tokens, 1)
+	require.Equal(t, "Stay", reply.Tokens[0].Text)
+	require.Equal(t, 4, reply.Tokens[0].Position)
+
+	for _, query := range []string{"lang=de", "lemmas=maybe"} {
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodPost, "/tag?"+query, strings.NewReader("The man"))
+		s.tagHandler(w, r)
+		require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, query)
+	}
+}
+
+func TestSentences(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/sentences", strings.NewReader("Mr. Holmes came. He sat down."))
+	s.sentencesHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Sentences []nlp.Sentence
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Equal(t, []nlp.Sentence{
+		{Text: "Mr. Holmes came.", Start: 0, End: 16, RuneStart: 0, RuneEnd: 16},
+		{Text: "He sat down.", Start: 17, End: 29, RuneStart: 17, RuneEnd: 29},
+	}, reply.Sentences)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/sentences?lines=true", strings.NewReader("A title\nThe text"))
+	s.sentencesHandler(w, r)
+	require.Equal(t, http.StatusOK, w.Result().StatusCode, "Result status")
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&reply))
+	require.Equal(t, []nlp.Sentence{
+		{Text: "A title", Start: 0, End: 7, RuneStart: 0, RuneEnd: 7},
+		{Text: "The text", Start: 8, End: 16, RuneStart: 8, RuneEnd: 16},
+	}, reply.Sentences)
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/sentences?lines=maybe", strings.NewReader("A title"))
+	s.sentencesHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, "Result status")
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/sentences", nil)
+	s.sentencesHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, "Result status")
+}
+
+func TestTokenizeLarge(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	// More than the 1MB that used to be read in memory
+	text := strings.Repeat("Who's on first? ", 200_000)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/tokenize", strings.NewReader(text))
+	s.tokenizeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Tokens []string
+		Error  string
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Empty(t, reply.Error)
+	require.Len(t, reply.Tokens, 600_000)
+	require.Equal(t, []string{"who", "on", "first"}, reply.Tokens[len(reply.Tokens)-3:])
+
+	w = httptest.NewRecorder()
+	r = httptest.NewRequest(http.MethodPost, "/tokenize", nil)
+	s.tokenizeHandler(w, r)
+	require.Equal(t, http.StatusBadRequest, w.Result().StatusCode, "Result status")
+}
+
+func TestIndexSearch(t *testing.T) {
+	s := Server{logger: log.Default(), index: nlp.NewIndex(nil), indexFile: filepath.Join(t.TempDir(), "nlpd.idx")}
+	r := mux.NewRouter()
+	r.HandleFunc("/index", s.indexStatsHandler).Methods(http.MethodGet)
+	r.HandleFunc("/index/{id}", s.indexHandler).Methods(http.MethodPut)
+	r.HandleFunc("/index/{id}", s.unindexHandler).Methods(http.MethodDelete)
+	r.HandleFunc("/search", s.searchHandler).Methods(http.MethodGet)
+
+	docs := map[string]string{
+		"a": "Sherlock Holmes lived in Baker Street.",
+		"b": "Doctor Watson wrote about Holmes.",
+		"c": "The red-headed league.",
+	}
+	for id, text := range docs {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/index/"+id, strings.NewReader(text)))
+		require.Equal(t, http.StatusOK, w.Code, "Result status")
+	}
+
+	search := func(query string) []string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q="+url.QueryEscape(query), nil))
+		require.Equal(t, http.StatusOK, w.Code, "Result status")
+		var reply struct {
+			Hits []nlp.Hit
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&reply))
+		var ids []string
+		for _, h := range reply.Hits {
+			ids = append(ids, h.ID)
+		}
+		return ids
+	}
+	require.Equal(t, []string{"b", "a"}, search("holmes"))
+	require.Equal(t, []string{"a"}, search(`"baker street"`))
+	require.Equal(t, []string{"b"}, search("holmes NOT street"))
+
+	// Update & delete
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/index/c", strings.NewReader("Holmes")))
+	require.Equal(t, http.StatusOK, w.Code, "Result status")
+	require.Contains(t, w.Body.String(), `"created":false`)
+	require.Empty(t, search("league"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/index/c", nil))
+	require.Equal(t, http.StatusOK, w.Code, "Result status")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/index/c", nil))
+	require.Equal(t, http.StatusNotFound, w.Code, "Result status")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/index", nil))
+	require.JSONEq(t, `{"documents": 2, "terms": 10}`, w.Body.String())
+
+	// Saved on every change
+	index, err := openIndex(s.indexFile)
+	require.NoError(t, err)
+	require.Equal(t, 2, index.Len())
+
+	for _, path := range []string{"/search?q=%22holmes", "/search", "/search?q=holmes&n=0"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusBadRequest, w.Code, path)
+	}
+}
+
+func TestIndexConcurrentPut(t *testing.T) {
+	s := Server{logger: log.Default(), index: nlp.NewIndex(nil), indexFile: filepath.Join(t.TempDir(), "nlpd.idx")}
+	r := mux.NewRouter()
+	r.HandleFunc("/index/{id}", s.indexHandler).Methods(http.MethodPut)
+
+	const n = 20
+	var (
+		wg      sync.WaitGroup
+		created atomic.Int32
+	)
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Half the requests add the same document
+			id := fmt.Sprintf("doc%d", i)
+			if i%2 == 0 {
+				id = "same"
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/index/"+id, strings.NewReader("Holmes")))
+			require.Equal(t, http.StatusOK, w.Code, "Result status")
+			if id == "same" && strings.Contains(w.Body.String(), `"created":true`) {
+				created.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), created.Load())
+
+	// Every change is saved
+	index, err := openIndex(s.indexFile)
+	require.NoError(t, err)
+	require.Equal(t, n/2+1, index.Len())
+}
+
+func TestSuggest(t *testing.T) {
+	s := Server{logger: log.Default()}
+	r := mux.NewRouter()
+	r.HandleFunc("/suggest/{word}", s.suggestHandler)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/suggest/detectve?n=2", nil))
+	require.Equal(t, http.StatusOK, w.Code, "Result status")
+	var reply struct {
+		Word        string
+		Suggestions []nlp.Suggestion
+	}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&reply))
+	require.Equal(t, "detectve", reply.Word)
+	require.Len(t, reply.Suggestions, 2)
+	require.Equal(t, "detective", reply.Suggestions[0].Word)
+	require.Equal(t, 1, reply.Suggestions[0].Distance)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/suggest/xqzwvk", nil))
+	require.Equal(t, http.StatusOK, w.Code, "Result status")
+	require.JSONEq(t, `{"word":"xqzwvk","suggestions":[]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/suggest/teh?n=0", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code, "Result status")
+}
+
+func TestKeywords(t *testing.T) {
+	s := Server{logger: log.Default()}
+	text := "Sherlock Holmes examined the orange pips. The orange pips came from Savannah, and Holmes knew the danger."
+
+	for _, query := range []string{"", "?method=rake", "?method=textrank&limit=1"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/keywords"+query, strings.NewReader(text))
+		s.keywordsHandler(w, r)
+
+		resp := w.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode, query)
+		var reply struct {
+			Keywords []nlp.Keyphrase
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+		require.NotEmpty(t, reply.Keywords, query)
+		if strings.Contains(query, "limit=1") {
+			require.Len(t, reply.Keywords, 1)
+		}
+
+		var phrases []string
+		for _, k := range reply.Keywords {
+			phrases = append(phrases, k.Phrase)
+		}
+		if strings.Contains(query, "rake") {
+			require.Contains(t, phrases, "orange pips", query)
+		} else {
+			require.Contains(t, phrases, "holmes", query) // TextRank keeps the top third of words
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/keywords?method=rake&lang=de", strings.NewReader("Der Hund schläft vor dem Haus"))
+	s.keywordsHandler(w, r)
+	require.Equal(t, http.StatusOK, w.Code, "Result status")
+	require.JSONEq(t, `{"keywords":[{"phrase":"hund schläft","score":4,"count":1},{"phrase":"haus","score":1,"count":1}]}`, w.Body.String())
+
+	for _, query := range []string{"?limit=0", "?method=lda", "?lang=de", "?method=rake&lang=ru"} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/keywords"+query, strings.NewReader(text))
+		s.keywordsHandler(w, r)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	s := Server{logger: log.Default()}
+	text := "The cat sat on the mat. The dog chased the cat around the mat. It rained. The dog and the cat slept on the mat."
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/summarize?n=2", strings.NewReader(text))
+	s.summarizeHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Sentences []nlp.Sentence
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Len(t, reply.Sentences, 2)
+	require.Less(t, reply.Sentences[0].Start, reply.Sentences[1].Start)
+	for _, sent := range reply.Sentences {
+		require.Equal(t, text[sent.Start:sent.End], sent.Text)
+	}
+
+	for _, query := range []string{"?n=0", "?n=x"} {
+		w = httptest.NewRecorder()
+		r = httptest.NewRequest(http.MethodPost, "/summarize"+query, strings.NewReader(text))
+		s.summarizeHandler(w, r)
+		require.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	c := nlp.NewClassifier()
+	c.Train([]nlp.Example{
+		{Text: "I was charged twice on my invoice", Label: "billing"},
+		{Text: "Please refund the payment", Label: "billing"},
+		{Text: "My package was never delivered", Label: "shipping"},
+		{Text: "When will the parcel arrive?", Label: "shipping"},
+	})
+	s := Server{logger: log.Default(), classifier: c}
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/classify", strings.NewReader("The payment on my invoice is wrong"))
+	s.classifyHandler(w, r)
+
+	resp := w.Result()
+	require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+	var reply struct {
+		Label       string
+		Predictions []nlp.Prediction
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	require.Equal(t, "billing", reply.Label)
+	require.Len(t, reply.Predictions, 2)
+	require.Equal(t, "billing", reply.Predictions[0].Label)
+
+	w = httptest.NewRecorder()
+	s.classifierHandler(w, httptest.NewRequest(http.MethodGet, "/classifier", nil))
+	require.JSONEq(t, `{"labels":["billing","shipping"]}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	s.classifyHandler(w, httptest.NewRequest(http.MethodPost, "/classify", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code, "Result status")
+
+	// No model
+	s = Server{logger: log.Default()}
+	w = httptest.NewRecorder()
+	s.classifyHandler(w, httptest.NewRequest(http.MethodPost, "/classify", strings.NewReader("refund")))
+	require.Equal(t, http.StatusNotFound, w.Code, "Result status")
+}
+
+func TestSentiment(t *testing.T) {
+	s := Server{logger: log.Default()}
+
+	score := func(text string) nlp.Sentiment {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/sentiment", strings.NewReader(text))
+		s.sentimentHandler(w, r)
+
+		resp := w.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode, "Result status")
+		var reply nlp.Sentiment
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+		return reply
+	}
+	require.Greater(t, score("The food was great!").Compound, 0.5)
+	require.Less(t, score("The food was not great.").Compound, 0.0)
+
+	w := httptest.NewRecorder()
+	s.sentimentHandler(w, httptest.NewRequest(http.MethodPost, "/sentiment", strings.NewReader("It rained.")))
+	require.JSONEq(t, `{"pos":0,"neg":0,"neu":1,"compound":0}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	s.sentimentHandler(w, httptest.NewRequest(http.MethodPost, "/sentiment", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code, "Result status")
+
+	w = httptest.NewRecorder()
+	s.sentimentHandler(w, httptest.NewRequest(http.MethodPost, "/sentiment?lang=de", strings.NewReader("Das Essen war gut")))
+	require.Equal(t, http.StatusBadRequest, w.Code, "Result status")
+}
@@ -0,0 +1,77 @@
+package nlp
+
+// Levenshtein returns the edit distance between a and b: the number of rune
+// insertions, deletions and substitutions turning a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra // Shorter rows
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// DamerauLevenshtein is like Levenshtein but also counts swapping two
+// adjacent runes as one edit, "teh" is 1 away from "the". Unlike the common
+// "optimal string alignment" variant, swapped runes may be edited again, so
+// "ca" is 2 away from "abc" (ca -> ac -> abc) and the distance is a metric,
+// as BKTree needs.
+func DamerauLevenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return len(ra) + len(rb)
+	}
+
+	// d[i+1][j+1] is the distance between ra[:i] and rb[:j], row and column
+	// 0 hold the maximal distance (Lowrance & Wagner's algorithm)
+	inf := len(ra) + len(rb)
+	d := make([][]int, len(ra)+2)
+	for i := range d {
+		d[i] = make([]int, len(rb)+2)
+		d[i][0] = inf
+		if i > 0 {
+			d[i][1] = i - 1
+		}
+	}
+	for j := 1; j < len(rb)+2; j++ {
+		d[0][j] = inf
+		d[1][j] = j - 1
+	}
+
+	lastRow := make(map[rune]int) // Rune -> last row where it is in ra
+	for i := 1; i <= len(ra); i++ {
+		lastCol := 0 // Last column where rb matched ra[i-1]
+		for j := 1; j <= len(rb); j++ {
+			k, l := lastRow[rb[j-1]], lastCol
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+				lastCol = j
+			}
+			d[i+1][j+1] = min(
+				d[i][j]+cost,              // Substitution
+				d[i+1][j]+1,               // Insertion
+				d[i][j+1]+1,               // Deletion
+				d[k][l]+(i-k-1)+1+(j-l-1), // Transposition
+			)
+		}
+		lastRow[ra[i-1]] = i
+	}
+	return d[len(ra)+1][len(rb)+1]
+}
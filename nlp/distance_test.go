@@ -0,0 +1,43 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b        string
+		levenshtein int
+		damerau     int
+	}{
+		{"", "", 0, 0},
+		{"", "abc", 3, 3},
+		{"abc", "abc", 0, 0},
+		{"kitten", "sitting", 3, 3},
+		{"teh", "the", 2, 1},
+		{"ca", "abc", 3, 2},
+		{"abcdef", "badcfe", 4, 3},
+		{"café", "cafe", 1, 1},
+		{"naïve", "nave", 1, 1},
+		{"日本語", "本日語", 2, 1},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.levenshtein, Levenshtein(tc.a, tc.b), "%q %q", tc.a, tc.b)
+		require.Equal(t, tc.levenshtein, Levenshtein(tc.b, tc.a), "%q %q", tc.b, tc.a)
+		require.Equal(t, tc.damerau, DamerauLevenshtein(tc.a, tc.b), "%q %q", tc.a, tc.b)
+		require.Equal(t, tc.damerau, DamerauLevenshtein(tc.b, tc.a), "%q %q", tc.b, tc.a)
+	}
+}
+
+func FuzzDamerauLevenshtein(f *testing.F) {
+	f.Add("teh", "the")
+	f.Add("ca", "abc")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		d := DamerauLevenshtein(a, b)
+		require.Equal(t, d, DamerauLevenshtein(b, a))
+		require.LessOrEqual(t, d, Levenshtein(a, b))
+		require.Equal(t, d == 0, a == b || string([]rune(a)) == string([]rune(b)))
+	})
+}
@@ -0,0 +1,187 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ArditZubaku/nlp/stemmer"
+)
+
+// TokenFilter is a step of an Analyzer, it may change, drop or add tokens.
+// Filters work on the term of a token, its Stem field, normalizing filters
+// also update Normalized.
+type TokenFilter interface {
+	Filter(tokens []Token) []Token
+}
+
+// FilterFunc adapts a function to the TokenFilter interface.
+type FilterFunc func(tokens []Token) []Token
+
+// Filter calls f(tokens).
+func (f FilterFunc) Filter(tokens []Token) []Token {
+	return f(tokens)
+}
+
+// normalizer returns a filter that applies fn to Normalized and Stem.
+func normalizer(fn func(string) string) TokenFilter {
+	return FilterFunc(func(tokens []Token) []Token {
+		for i := range tokens {
+			tokens[i].Normalized = fn(tokens[i].Normalized)
+			tokens[i].Stem = fn(tokens[i].Stem)
+		}
+		return tokens
+	})
+}
+
+// keep returns a filter that drops the tokens for which fn returns false.
+func keep(fn func(Token) bool) TokenFilter {
+	return FilterFunc(func(tokens []Token) []Token {
+		out := tokens[:0]
+		for _, tok := range tokens {
+			if fn(tok) {
+				out = append(out, tok)
+			}
+		}
+		return out
+	})
+}
+
+// Lowercase case folds tokens, see Fold.
+func Lowercase() TokenFilter {
+	return normalizer(Fold)
+}
+
+// ASCIIFold replaces letters with diacritics by their ASCII base letters
+// ("café" -> "cafe", "straße" -> "strasse").
+func ASCIIFold() TokenFilter {
+	return normalizer(asciiFold)
+}
+
+// StopWords drops tokens whose normalized form or term is one of words.
+// Words are case folded.
+func StopWords(words ...string) TokenFilter {
+	stop := make(map[string]bool, len(words))
+	for _, w := range words {
+		stop[Fold(w)] = true
+	}
+	return keep(func(tok Token) bool {
+		return !stop[tok.Normalized] && !stop[tok.Stem]
+	})
+}
+
+// StemFilter stems token terms with s.
+func StemFilter(s stemmer.Stemmer) TokenFilter {
+	return FilterFunc(func(tokens []Token) []Token {
+		for i := range tokens {
+			tokens[i].Stem = s.Stem(tokens[i].Stem)
+		}
+		return tokens
+	})
+}
+
+// MinLength drops tokens whose term is shorter than n letters.
+func MinLength(n int) TokenFilter {
+	return keep(func(tok Token) bool {
+		return utf8.RuneCountInString(tok.Stem) >= n
+	})
+}
+
+// MaxLength drops tokens whose term is longer than n letters.
+func MaxLength(n int) TokenFilter {
+	return keep(func(tok Token) bool {
+		return utf8.RuneCountInString(tok.Stem) <= n
+	})
+}
+
+// Synonyms adds a token for each synonym of a term, at the same position
+// and offsets as the original token. Place it before StemFilter to have
+// the synonyms stemmed too.
+func Synonyms(synonyms map[string][]string) TokenFilter {
+	return FilterFunc(func(tokens []Token) []Token {
+		var out []Token
+		for _, tok := range tokens {
+			out = append(out, tok)
+			for _, syn := range synonyms[tok.Stem] {
+				tok.Normalized, tok.Stem = syn, syn
+				out = append(out, tok)
+			}
+		}
+		return out
+	})
+}
+
+var asciiFolds = map[rune]string{}
+
+func init() {
+	for ascii, letters := range map[string]string{
+		"a":  "àáâãäåāăąǎȁȃȧạảấầẩẫậắằẳẵặ",
+		"A":  "ÀÁÂÃÄÅĀĂĄǍȀȂȦẠẢẤẦẨẪẬẮẰẲẴẶ",
+		"c":  "çćĉċč",
+		"C":  "ÇĆĈĊČ",
+		"d":  "ďđð",
+		"D":  "ĎĐÐ",
+		"e":  "èéêëēĕėęěȅȇẹẻẽếềểễệ",
+		"E":  "ÈÉÊËĒĔĖĘĚȄȆẸẺẼẾỀỂỄỆ",
+		"g":  "ĝğġģǧ",
+		"G":  "ĜĞĠĢǦ",
+		"h":  "ĥħ",
+		"H":  "ĤĦ",
+		"i":  "ìíîïĩīĭįıǐȉȋỉị",
+		"I":  "ÌÍÎÏĨĪĬĮİǏȈȊỈỊ",
+		"j":  "ĵ",
+		"J":  "Ĵ",
+		"k":  "ķ",
+		"K":  "Ķ",
+		"l":  "ĺļľŀł",
+		"L":  "ĹĻĽĿŁ",
+		"n":  "ñńņňŉ",
+		"N":  "ÑŃŅŇ",
+		"o":  "òóôõöøōŏőơǒȍȏọỏốồổỗộớờởỡợ",
+		"O":  "ÒÓÔÕÖØŌŎŐƠǑȌȎỌỎỐỒỔỖỘỚỜỞỠỢ",
+		"r":  "ŕŗř",
+		"R":  "ŔŖŘ",
+		"s":  "śŝşšș",
+		"S":  "ŚŜŞŠȘ",
+		"t":  "ţťŧț",
+		"T":  "ŢŤŦȚ",
+		"u":  "ùúûüũūŭůűųưǔǖǘǚǜȕȗụủứừửữự",
+		"U":  "ÙÚÛÜŨŪŬŮŰŲƯǓǕǗǙǛȔȖỤỦỨỪỬỮỰ",
+		"w":  "ŵ",
+		"W":  "Ŵ",
+		"y":  "ýÿŷỳỵỷỹ",
+		"Y":  "ÝŸŶỲỴỶỸ",
+		"z":  "źżž",
+		"Z":  "ŹŻŽ",
+		"ae": "æ",
+		"AE": "Æ",
+		"oe": "œ",
+		"OE": "Œ",
+		"ss": "ß",
+		"SS": "ẞ",
+		"th": "þ",
+		"TH": "Þ",
+	} {
+		for _, r := range letters {
+			asciiFolds[r] = ascii
+		}
+	}
+}
+
+// asciiFold replaces letters with diacritics in s by ASCII letters, and
+// drops combining marks.
+func asciiFold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if a, ok := asciiFolds[r]; ok {
+			b.WriteString(a)
+			continue
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
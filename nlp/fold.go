@@ -0,0 +1,116 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// fullFolds holds the full case foldings (status F in Unicode's
+// CaseFolding.txt) where a rune folds to more than one rune.
+var fullFolds = map[rune]string{
+	0x00DF: "ss",                 // ß
+	0x1E9E: "ss",                 // ẞ
+	0x0130: "i\u0307",            // İ
+	0x0149: "\u02BCn",            // ŉ
+	0x01F0: "j\u030C",            // ǰ
+	0x0390: "\u03B9\u0308\u0301", // ΐ
+	0x03B0: "\u03C5\u0308\u0301", // ΰ
+	0x0587: "\u0565\u0582",       // և
+	0x1E96: "h\u0331",            // ẖ
+	0x1E97: "t\u0308",            // ẗ
+	0x1E98: "w\u030A",            // ẘ
+	0x1E99: "y\u030A",            // ẙ
+	0x1E9A: "a\u02BE",            // ẚ
+	0x1F50: "\u03C5\u0313",       // ὐ
+	0x1F52: "\u03C5\u0313\u0300", // ὒ
+	0x1F54: "\u03C5\u0313\u0301", // ὔ
+	0x1F56: "\u03C5\u0313\u0342", // ὖ
+	0x1FB2: "\u1F70\u03B9",       // ᾲ
+	0x1FB3: "\u03B1\u03B9",       // ᾳ
+	0x1FB4: "\u03AC\u03B9",       // ᾴ
+	0x1FB6: "\u03B1\u0342",       // ᾶ
+	0x1FB7: "\u03B1\u0342\u03B9", // ᾷ
+	0x1FBC: "\u03B1\u03B9",       // ᾼ
+	0x1FC2: "\u1F74\u03B9",       // ῂ
+	0x1FC3: "\u03B7\u03B9",       // ῃ
+	0x1FC4: "\u03AE\u03B9",       // ῄ
+	0x1FC6: "\u03B7\u0342",       // ῆ
+	0x1FC7: "\u03B7\u0342\u03B9", // ῇ
+	0x1FCC: "\u03B7\u03B9",       // ῌ
+	0x1FD2: "\u03B9\u0308\u0300", // ῒ
+	0x1FD3: "\u03B9\u0308\u0301", // ΐ
+	0x1FD6: "\u03B9\u0342",       // ῖ
+	0x1FD7: "\u03B9\u0308\u0342", // ῗ
+	0x1FE2: "\u03C5\u0308\u0300", // ῢ
+	0x1FE3: "\u03C5\u0308\u0301", // ΰ
+	0x1FE4: "\u03C1\u0313",       // ῤ
+	0x1FE6: "\u03C5\u0342",       // ῦ
+	0x1FE7: "\u03C5\u0308\u0342", // ῧ
+	0x1FF2: "\u1F7C\u03B9",       // ῲ
+	0x1FF3: "\u03C9\u03B9",       // ῳ
+	0x1FF4: "\u03CE\u03B9",       // ῴ
+	0x1FF6: "\u03C9\u0342",       // ῶ
+	0x1FF7: "\u03C9\u0342\u03B9", // ῷ
+	0x1FFC: "\u03C9\u03B9",       // ῼ
+	0xFB00: "ff",                 // ﬀ
+	0xFB01: "fi",                 // ﬁ
+	0xFB02: "fl",                 // ﬂ
+	0xFB03: "ffi",                // ﬃ
+	0xFB04: "ffl",                // ﬄ
+	0xFB05: "st",                 // ﬅ
+	0xFB06: "st",                 // ﬆ
+	0xFB13: "\u0574\u0576",       // ﬓ
+	0xFB14: "\u0574\u0565",       // ﬔ
+	0xFB15: "\u0574\u056B",       // ﬕ
+	0xFB16: "\u057E\u0576",       // ﬖ
+	0xFB17: "\u0574\u056D",       // ﬗ
+}
+
+func init() {
+	// Greek letters with ypogegrammeni (iota subscript), U+1F80-U+1FAF.
+	// Each group of 8 lower case letters is followed by its 8 title case
+	// forms, both fold to the letter without the iota followed by ι.
+	for i, base := range []rune{0x1F00, 0x1F20, 0x1F60} {
+		for k := range rune(8) {
+			fold := string(base+k) + "\u03B9"
+			fullFolds[0x1F80+rune(i)*16+k] = fold
+			fullFolds[0x1F88+rune(i)*16+k] = fold
+		}
+	}
+}
+
+// Fold returns s with full Unicode case folding, which is meant for
+// caseless matching: "Straße", "STRASSE" and "strasse" all fold to
+// "strasse". Unlike strings.ToLower, the result may be longer than s.
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if f, ok := fullFolds[r]; ok {
+			b.WriteString(f)
+			continue
+		}
+		b.WriteRune(foldRune(r))
+	}
+	return b.String()
+}
+
+// foldRune returns the simple case folding of r.
+func foldRune(r rune) rune {
+	switch {
+	case r < utf8.RuneSelf:
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	case r == 'ı': // Dotless i has no folding
+		return r
+	case unicode.Is(unicode.Cherokee, r):
+		// Cherokee folds to upper case, its lower case letters came later
+		return unicode.ToUpper(r)
+	}
+	// Going through upper case maps variants such as ς, ſ and ϐ to the
+	// lower case letter they fold to
+	return unicode.ToLower(unicode.ToUpper(r))
+}
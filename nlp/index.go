@@ -0,0 +1,379 @@
+package nlp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2  // Term frequency saturation
+	bm25B  = 0.75 // Document length normalization
+)
+
+// Index is an in-memory inverted index of documents, with positional
+// postings, searched with BM25 ranking. Documents are analyzed with the
+// Analyzer of the index, the same is used for queries. An Index is safe for
+// concurrent use.
+type Index struct {
+	analyzer *Analyzer
+
+	mu       sync.RWMutex
+	docs     map[string]*indexDoc
+	postings map[string]map[string][]int // term -> document ID -> positions
+	totalLen int                         // Sum of document lengths
+}
+
+type indexDoc struct {
+	length int      // Number of tokens
+	terms  []string // Distinct terms, to remove the document
+}
+
+// Hit is a document matching a search.
+type Hit struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+// NewIndex returns an empty index analyzing documents with a, nil means the
+// "standard" preset, which is what Tokenize does.
+func NewIndex(a *Analyzer) *Index {
+	if a == nil {
+		a, _ = Preset("standard")
+	}
+	return &Index{
+		analyzer: a,
+		docs:     make(map[string]*indexDoc),
+		postings: make(map[string]map[string][]int),
+	}
+}
+
+// Add indexes text as document id, replacing the document if it exists, and
+// reports whether id is a new document.
+func (ix *Index) Add(id, text string) bool {
+	tokens := ix.analyzer.Analyze(text)
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	replaced := ix.remove(id)
+	doc := &indexDoc{length: len(tokens)}
+	for _, tok := range tokens {
+		docs, ok := ix.postings[tok.Stem]
+		if !ok {
+			docs = make(map[string][]int)
+			ix.postings[tok.Stem] = docs
+		}
+		if _, ok := docs[id]; !ok {
+			doc.terms = append(doc.terms, tok.Stem)
+		}
+		docs[id] = append(docs[id], tok.Position)
+	}
+	ix.docs[id] = doc
+	ix.totalLen += doc.length
+	return !replaced
+}
+
+// Remove removes document id and reports whether it was in the index.
+func (ix *Index) Remove(id string) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	return ix.remove(id)
+}
+
+func (ix *Index) remove(id string) bool {
+	doc, ok := ix.docs[id]
+	if !ok {
+		return false
+	}
+
+	for _, term := range doc.terms {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	delete(ix.docs, id)
+	ix.totalLen -= doc.length
+	return true
+}
+
+// Len returns the number of documents.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.docs)
+}
+
+// Terms returns the number of distinct terms.
+func (ix *Index) Terms() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.postings)
+}
+
+// Has reports whether document id is in the index.
+func (ix *Index) Has(id string) bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	_, ok := ix.docs[id]
+	return ok
+}
+
+// Search returns the documents matching query, best first, at most n if n
+// is positive. Documents matching only through NOT have a score of 0.
+//
+// Query syntax:
+//   - Words match documents with their term, as analyzed by the index.
+//   - "Quoted phrases" match documents with the terms in order.
+//   - Words and phrases separated by spaces or AND must all match.
+//   - a OR b matches either, AND binds tighter than OR.
+//   - NOT a (or -a) excludes documents matching a.
+//   - Parentheses group.
+//
+// Words that analyze to nothing, like stop words, are ignored.
+func (ix *Index) Search(query string, n int) ([]Hit, error) {
+	q, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	matches, _ := q.match(ix)
+	var hits []Hit
+	for id, score := range matches {
+		hits = append(hits, Hit{ID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if n > 0 && len(hits) > n {
+		hits = hits[:n]
+	}
+	return hits, nil
+}
+
+// bm25 returns the BM25 score of term for a document where it appears tf
+// times.
+func (ix *Index) bm25(term, id string, tf int) float64 {
+	n := float64(len(ix.docs))
+	df := float64(len(ix.postings[term]))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	avgLen := float64(ix.totalLen) / n
+	docLen := float64(ix.docs[id].length)
+	freq := float64(tf)
+	return idf * freq * (bm25K1 + 1) / (freq + bm25K1*(1-bm25B+bm25B*docLen/avgLen))
+}
+
+// indexMagic starts a saved index, the last byte is the format version.
+const indexMagic = "NLPIDX\x01"
+
+// maxIndexString is the maximal length of a document ID or term in a saved
+// index, so a corrupt file doesn't allocate gigabytes.
+const maxIndexString = 1 << 20
+
+// Save writes ix in a compact binary format: documents are numbered in ID
+// order, postings lists are sorted and all numbers are delta encoded
+// varints. The analyzer is not saved.
+func (ix *Index) Save(w io.Writer) error {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	ids := make([]string, 0, len(ix.docs))
+	for id := range ix.docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	nums := make(map[string]int, len(ids)) // ID -> document number
+	for i, id := range ids {
+		nums[id] = i
+	}
+
+	terms := make([]string, 0, len(ix.postings))
+	for term := range ix.postings {
+		terms = append(terms, term)
+	}
+	sort.Strings(terms)
+
+	bw := bufio.NewWriter(w)
+	var buf []byte
+	putString := func(s string) {
+		buf = binary.AppendUvarint(buf, uint64(len(s)))
+		buf = append(buf, s...)
+	}
+
+	buf = append(buf, indexMagic...)
+	buf = binary.AppendUvarint(buf, uint64(len(ids)))
+	for _, id := range ids {
+		putString(id)
+		buf = binary.AppendUvarint(buf, uint64(ix.docs[id].length))
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(terms)))
+	for _, term := range terms {
+		docs := make([]int, 0, len(ix.postings[term]))
+		for id := range ix.postings[term] {
+			docs = append(docs, nums[id])
+		}
+		sort.Ints(docs)
+
+		putString(term)
+		buf = binary.AppendUvarint(buf, uint64(len(docs)))
+		prevDoc := 0
+		for _, doc := range docs {
+			positions := ix.postings[term][ids[doc]]
+			buf = binary.AppendUvarint(buf, uint64(doc-prevDoc))
+			buf = binary.AppendUvarint(buf, uint64(len(positions)))
+			prevPos := 0
+			for _, pos := range positions {
+				buf = binary.AppendUvarint(buf, uint64(pos-prevPos))
+				prevPos = pos
+			}
+			prevDoc = doc
+		}
+
+		// Flush each term, so buf stays small
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		buf = buf[:0]
+	}
+
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// LoadIndex reads an index written by Save. The analyzer is not saved, use
+// the one of the saved index.
+func LoadIndex(r io.Reader, a *Analyzer) (*Index, error) {
+	br := bufio.NewReader(r)
+	ix := NewIndex(a)
+	if err := ix.load(br); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("bad index: %w", err)
+	}
+	return ix, nil
+}
+
+func (ix *Index) load(r *bufio.Reader) error {
+	magic := make([]byte, len(indexMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return err
+	}
+	if string(magic) != indexMagic {
+		return errors.New("unknown format")
+	}
+
+	readInt := func() (int, error) {
+		n, err := binary.ReadUvarint(r)
+		if err == nil && n > math.MaxInt32 {
+			err = fmt.Errorf("number too large (%d)", n)
+		}
+		return int(n), err
+	}
+	readString := func() (string, error) {
+		n, err := readInt()
+		if err != nil {
+			return "", err
+		}
+		if n > maxIndexString {
+			return "", fmt.Errorf("string too long (%d)", n)
+		}
+		b := make([]byte, n)
+		_, err = io.ReadFull(r, b)
+		return string(b), err
+	}
+
+	numDocs, err := readInt()
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for range numDocs {
+		id, err := readString()
+		if err != nil {
+			return err
+		}
+		length, err := readInt()
+		if err != nil {
+			return err
+		}
+		if _, ok := ix.docs[id]; ok {
+			return fmt.Errorf("duplicate document %q", id)
+		}
+		ids = append(ids, id)
+		ix.docs[id] = &indexDoc{length: length}
+		ix.totalLen += length
+	}
+
+	numTerms, err := readInt()
+	if err != nil {
+		return err
+	}
+	for range numTerms {
+		term, err := readString()
+		if err != nil {
+			return err
+		}
+		numPostings, err := readInt()
+		if err != nil {
+			return err
+		}
+		if numPostings == 0 {
+			return fmt.Errorf("no documents for %q", term)
+		}
+
+		docs := make(map[string][]int)
+		doc := 0
+		for i := range numPostings {
+			delta, err := readInt()
+			if err != nil {
+				return err
+			}
+			doc += delta
+			if doc >= len(ids) || (i > 0 && delta == 0) {
+				return fmt.Errorf("bad document number %d for %q", doc, term)
+			}
+			numPositions, err := readInt()
+			if err != nil {
+				return err
+			}
+
+			var positions []int
+			pos := 0
+			for range numPositions {
+				delta, err := readInt()
+				if err != nil {
+					return err
+				}
+				pos += delta
+				positions = append(positions, pos)
+			}
+			docs[ids[doc]] = positions
+			d := ix.docs[ids[doc]]
+			d.terms = append(d.terms, term)
+		}
+		ix.postings[term] = docs
+	}
+	return nil
+}
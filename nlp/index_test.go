@@ -0,0 +1,198 @@
+package nlp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var indexDocs = map[string]string{
+	"holmes":  "Sherlock Holmes lived in Baker Street with Doctor Watson.",
+	"watson":  "Doctor Watson wrote about the cases of Sherlock Holmes, Holmes, Holmes.",
+	"league":  "The red-headed league met in a London office.",
+	"street":  "Baker Street is a street in London.",
+	"reverse": "The street of the baker.",
+}
+
+func newTestIndex() *Index {
+	ix := NewIndex(nil)
+	for id, text := range indexDocs {
+		ix.Add(id, text)
+	}
+	return ix
+}
+
+// hitIDs returns the IDs of hits, in order.
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func TestIndexAddRemove(t *testing.T) {
+	ix := newTestIndex()
+	require.Equal(t, len(indexDocs), ix.Len())
+	require.True(t, ix.Has("league"))
+	terms := ix.Terms()
+
+	// Update
+	require.False(t, ix.Add("league", "Moriarty"))
+	require.Equal(t, len(indexDocs), ix.Len())
+	hits, err := ix.Search("league", 0)
+	require.NoError(t, err)
+	require.Empty(t, hits)
+	hits, err = ix.Search("moriarty", 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"league"}, hitIDs(hits))
+
+	require.True(t, ix.Remove("league"))
+	require.False(t, ix.Remove("league"))
+	require.False(t, ix.Has("league"))
+	require.Equal(t, len(indexDocs)-1, ix.Len())
+	require.Less(t, ix.Terms(), terms) // Terms only in "league" are gone
+
+	ix.Add("league", indexDocs["league"])
+	require.Equal(t, terms, ix.Terms())
+}
+
+func TestIndexSearch(t *testing.T) {
+	ix := newTestIndex()
+
+	testCases := []struct {
+		query string
+		ids   []string
+	}{
+		{"holmes", []string{"watson", "holmes"}},
+		// Same analysis as documents
+		{"STREETS", []string{"street", "holmes", "reverse"}},
+		{"baker street", []string{"street", "holmes", "reverse"}},
+		{"baker AND london", []string{"street"}},
+		{`"baker street"`, []string{"street", "holmes"}},
+		{`"street baker"`, nil},
+		{"watson OR london", []string{"holmes", "league", "street", "watson"}},
+		{"baker NOT london", []string{"holmes", "reverse"}},
+		{"baker -london", []string{"holmes", "reverse"}},
+		{"NOT street", []string{"league", "watson"}},
+		{"(london OR watson) NOT holmes", []string{"league", "street"}},
+		{"london OR watson AND doctor", []string{"holmes", "league", "street", "watson"}},
+		{"moriarty", nil},
+		{"holmes moriarty", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			hits, err := ix.Search(tc.query, 0)
+			require.NoError(t, err)
+			require.ElementsMatch(t, tc.ids, hitIDs(hits))
+		})
+	}
+}
+
+func TestIndexRanking(t *testing.T) {
+	ix := newTestIndex()
+
+	hits, err := ix.Search("holmes", 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"watson", "holmes"}, hitIDs(hits))
+
+	hits, err = ix.Search("street", 0)
+	require.NoError(t, err)
+	// "street" has it twice in a short document
+	require.Equal(t, "street", hits[0].ID)
+	for i := 1; i < len(hits); i++ {
+		require.GreaterOrEqual(t, hits[i-1].Score, hits[i].Score)
+	}
+
+	hits, err = ix.Search("street", 1)
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+
+	// Rare terms weigh more
+	hits, err = ix.Search("league OR street", 0)
+	require.NoError(t, err)
+	require.Equal(t, "league", hits[0].ID)
+
+	// Documents matching only through NOT have no score
+	hits, err = ix.Search("NOT holmes", 0)
+	require.NoError(t, err)
+	for _, h := range hits {
+		require.Equal(t, 0.0, h.Score)
+	}
+}
+
+func TestIndexStopWords(t *testing.T) {
+	a, _ := Preset("search")
+	ix := NewIndex(a)
+	ix.Add("a", "The man of the hour")
+	ix.Add("b", "The man in the hour")
+	ix.Add("c", "Man of hour")
+
+	// Stop words are ignored, but leave a gap in phrases
+	hits, err := ix.Search(`"man of the hour"`, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, hitIDs(hits))
+
+	hits, err = ix.Search("the", 0)
+	require.NoError(t, err)
+	require.Empty(t, hits)
+
+	hits, err = ix.Search("the OR hour", 0)
+	require.NoError(t, err)
+	require.Len(t, hits, 3)
+}
+
+func TestIndexSaveLoad(t *testing.T) {
+	ix := newTestIndex()
+
+	var buf bytes.Buffer
+	require.NoError(t, ix.Save(&buf))
+	data := buf.Bytes()
+
+	loaded, err := LoadIndex(bytes.NewReader(data), nil)
+	require.NoError(t, err)
+	require.Equal(t, ix.Len(), loaded.Len())
+	require.Equal(t, ix.Terms(), loaded.Terms())
+
+	for _, query := range []string{"holmes", `"baker street"`, "london NOT baker"} {
+		want, err := ix.Search(query, 0)
+		require.NoError(t, err)
+		got, err := loaded.Search(query, 0)
+		require.NoError(t, err)
+		require.Equal(t, want, got, query)
+	}
+
+	// Removing from a loaded index
+	require.True(t, loaded.Remove("league"))
+	hits, err := loaded.Search("league", 0)
+	require.NoError(t, err)
+	require.Empty(t, hits)
+
+	// Saving is deterministic
+	var again bytes.Buffer
+	require.NoError(t, ix.Save(&again))
+	require.Equal(t, data, again.Bytes())
+
+	_, err = LoadIndex(strings.NewReader("NLPIDX\x02"), nil)
+	require.Error(t, err)
+	for i := len(indexMagic); i < len(data); i += 7 {
+		_, err = LoadIndex(bytes.NewReader(data[:i]), nil)
+		require.Error(t, err, "truncated at %d", i)
+	}
+}
+
+func TestIndexEmpty(t *testing.T) {
+	ix := NewIndex(nil)
+	hits, err := ix.Search("holmes", 0)
+	require.NoError(t, err)
+	require.Empty(t, hits)
+
+	var buf bytes.Buffer
+	require.NoError(t, ix.Save(&buf))
+	loaded, err := LoadIndex(&buf, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, loaded.Len())
+}
@@ -0,0 +1,268 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TextRank parameters
+const (
+	textRankDamping    = 0.85
+	textRankWindow     = 2 // Candidate words at most this far apart are linked
+	textRankIterations = 100
+	textRankTolerance  = 1e-6
+)
+
+// Keyphrase is a phrase of a document with its score.
+type Keyphrase struct {
+	Phrase string  `json:"phrase"` // Case folded words, separated by a space
+	Score  float64 `json:"score"`
+	Count  int     `json:"count"` // Occurrences in the document
+}
+
+// RAKE returns the n best keyphrases of text, using Rapid Automatic Keyword
+// Extraction: candidate phrases are the runs of words between stop words and
+// punctuation, each word is scored by its degree (the number of words in
+// the phrases it is in) divided by its frequency, and a phrase by the sum of
+// its word scores. RAKE favors long phrases. nil stopWords means the
+// English list.
+func RAKE(text string, stopWords []string, n int) []Keyphrase {
+	stop := stopWordSet(stopWords)
+	var phrases [][]string
+	for _, fragment := range fragments(text, false) {
+		var phrase []string
+		for _, tok := range fragment {
+			word := normalizeWord(tok.Text)
+			if stop[word] || !isSpellWord(word) {
+				if len(phrase) > 0 {
+					phrases = append(phrases, phrase)
+				}
+				phrase = nil
+				continue
+			}
+			phrase = append(phrase, word)
+		}
+		if len(phrase) > 0 {
+			phrases = append(phrases, phrase)
+		}
+	}
+
+	freq := make(map[string]int)
+	degree := make(map[string]int)
+	for _, phrase := range phrases {
+		for _, word := range phrase {
+			freq[word]++
+			degree[word] += len(phrase)
+		}
+	}
+
+	scores := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, phrase := range phrases {
+		key := strings.Join(phrase, " ")
+		if counts[key] == 0 {
+			for _, word := range phrase {
+				scores[key] += float64(degree[word]) / float64(freq[word])
+			}
+		}
+		counts[key]++
+	}
+	return topKeyphrases(scores, counts, n)
+}
+
+// TextRank returns the n best keyphrases of English text, using TextRank
+// (Mihalcea & Tarau, 2004): nouns and adjectives, found with Tag, are the
+// vertices of a graph where words close to each other are linked. Words are
+// ranked with PageRank over the graph, and the adjacent words among the top
+// third become phrases, scored by the sum of their word ranks.
+func TextRank(text string, n int) []Keyphrase {
+	stop := stopWordSet(nil)
+	isCandidate := func(tok Token) bool {
+		switch tok.POS {
+		case NOUN, PROPN, ADJ:
+			word := normalizeWord(tok.Text)
+			return !stop[word] && isSpellWord(word)
+		}
+		return false
+	}
+
+	// Vertices are numbered in text order, so ranking is deterministic
+	frags := fragments(text, true)
+	ids := make(map[string]int)
+	var (
+		words []string          // Vertex -> word
+		links []map[int]float64 // Vertex -> linked vertex -> weight
+	)
+	for _, fragment := range frags {
+		var window []int
+		for _, tok := range fragment {
+			if !isCandidate(tok) {
+				continue
+			}
+			word := normalizeWord(tok.Text)
+			id, ok := ids[word]
+			if !ok {
+				id = len(links)
+				ids[word] = id
+				words = append(words, word)
+				links = append(links, make(map[int]float64))
+			}
+			for _, other := range window {
+				if other != id {
+					links[id][other]++
+					links[other][id]++
+				}
+			}
+			window = append(window, id)
+			if len(window) >= textRankWindow {
+				window = window[1:]
+			}
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+
+	ranks := pageRank(links)
+	top := make([]int, len(ranks))
+	for i := range top {
+		top[i] = i
+	}
+	sort.SliceStable(top, func(i, j int) bool { return ranks[top[i]] > ranks[top[j]] })
+	keep := make(map[string]float64) // Keyword -> rank
+	for _, id := range top[:max(len(top)/3, 1)] {
+		keep[words[id]] = ranks[id]
+	}
+
+	scores := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, fragment := range frags {
+		var phrase []string
+		score := 0.0
+		flush := func() {
+			if len(phrase) > 0 {
+				key := strings.Join(phrase, " ")
+				scores[key] = score
+				counts[key]++
+			}
+			phrase, score = nil, 0
+		}
+		for _, tok := range fragment {
+			word := normalizeWord(tok.Text)
+			rank, ok := keep[word]
+			if !ok || !isCandidate(tok) {
+				flush()
+				continue
+			}
+			phrase = append(phrase, word)
+			score += rank
+		}
+		flush()
+	}
+	return topKeyphrases(scores, counts, n)
+}
+
+// pageRank returns the ranks of the vertices of a weighted undirected graph.
+func pageRank(links []map[int]float64) []float64 {
+	// Sorted neighbours, so the sums are in a deterministic order
+	type edge struct {
+		to     int
+		weight float64
+	}
+	edges := make([][]edge, len(links))
+	out := make([]float64, len(links)) // Total weight of the links of a vertex
+	for i, m := range links {
+		for j, w := range m {
+			edges[i] = append(edges[i], edge{j, w})
+			out[i] += w
+		}
+		sort.Slice(edges[i], func(a, b int) bool { return edges[i][a].to < edges[i][b].to })
+	}
+
+	ranks := make([]float64, len(links))
+	for i := range ranks {
+		ranks[i] = 1
+	}
+	next := make([]float64, len(links))
+	for range textRankIterations {
+		delta := 0.0
+		for i := range ranks {
+			sum := 0.0
+			for _, e := range edges[i] {
+				sum += e.weight / out[e.to] * ranks[e.to]
+			}
+			next[i] = 1 - textRankDamping + textRankDamping*sum
+			delta = max(delta, math.Abs(next[i]-ranks[i]))
+		}
+		ranks, next = next, ranks
+		if delta < textRankTolerance {
+			break
+		}
+	}
+	return ranks
+}
+
+// fragments splits the sentences of text into runs of words not separated
+// by punctuation. With tag, the words have their part of speech, tagged
+// with the whole sentence.
+func fragments(text string, tag bool) [][]Token {
+	var out [][]Token
+	for _, s := range Sentences(text) {
+		tokens := SplitWords(s.Text)
+		if tag {
+			Tag(tokens)
+		}
+
+		start := 0
+		for i := 1; i < len(tokens); i++ {
+			if strings.ContainsFunc(s.Text[tokens[i-1].End:tokens[i].Start], isPhraseBreak) {
+				out = append(out, tokens[start:i])
+				start = i
+			}
+		}
+		if start < len(tokens) {
+			out = append(out, tokens[start:])
+		}
+	}
+	return out
+}
+
+// isPhraseBreak reports whether r between two words separates phrases, which
+// is punctuation other than hyphens: "well-known writer" is a phrase.
+func isPhraseBreak(r rune) bool {
+	return !unicode.IsSpace(r) && r != '-'
+}
+
+// stopWordSet returns the normalized words as a set, nil means the English
+// stop words.
+func stopWordSet(words []string) map[string]bool {
+	if words == nil {
+		words, _ = StopWordList("en")
+	}
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[normalizeWord(w)] = true
+	}
+	return set
+}
+
+// topKeyphrases returns the n phrases with the best scores, at most n if n
+// is positive.
+func topKeyphrases(scores map[string]float64, counts map[string]int, n int) []Keyphrase {
+	phrases := make([]Keyphrase, 0, len(scores))
+	for phrase, score := range scores {
+		phrases = append(phrases, Keyphrase{Phrase: phrase, Score: score, Count: counts[phrase]})
+	}
+	sort.Slice(phrases, func(i, j int) bool {
+		if phrases[i].Score != phrases[j].Score {
+			return phrases[i].Score > phrases[j].Score
+		}
+		return phrases[i].Phrase < phrases[j].Phrase
+	})
+	if n > 0 && len(phrases) > n {
+		phrases = phrases[:n]
+	}
+	return phrases
+}
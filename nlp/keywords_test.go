@@ -0,0 +1,86 @@
+package nlp
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The sample abstract of the RAKE paper (Rose et al., 2010)
+const rakeAbstract = `Compatibility of systems of linear constraints over the set of natural numbers. Criteria of compatibility of a system of linear Diophantine equations, strict inequations, and nonstrict inequations are considered. Upper bounds for components of a minimal set of solutions and algorithms of construction of minimal generating sets of solutions for all types of systems are given. These criteria and the corresponding algorithms for constructing a minimal supporting set of solutions can be used in solving all the considered types of systems and systems of mixed types.`
+
+func phrases(keyphrases []Keyphrase) []string {
+	var out []string
+	for _, k := range keyphrases {
+		out = append(out, k.Phrase)
+	}
+	return out
+}
+
+func TestRAKE(t *testing.T) {
+	keyphrases := RAKE(rakeAbstract, nil, 8)
+	require.Equal(t, []string{
+		"minimal generating sets",
+		"linear diophantine equations",
+		"minimal supporting set",
+		"minimal set",
+		"linear constraints",
+		"natural numbers",
+		"nonstrict inequations",
+		"strict inequations",
+	}, phrases(keyphrases))
+	require.InDelta(t, 8.667, keyphrases[0].Score, 0.001)
+	require.InDelta(t, 4, keyphrases[7].Score, 0.001)
+
+	all := RAKE(rakeAbstract, nil, 0)
+	require.Greater(t, len(all), 8)
+	for _, k := range all {
+		if k.Phrase == "systems" {
+			require.Equal(t, 4, k.Count)
+		}
+	}
+
+	// Custom stop words, punctuation always splits phrases
+	require.Equal(t, []string{"the cat sat", "the mat"}, phrases(RAKE("The cat sat on the mat.", []string{"on"}, 0)))
+	require.Equal(t, []string{"a b", "c"}, phrases(RAKE("A b, c", []string{}, 0)))
+	require.Empty(t, RAKE("", nil, 5))
+}
+
+func TestTextRank(t *testing.T) {
+	keyphrases := TextRank(rakeAbstract, 0)
+	require.Contains(t, phrases(keyphrases), "minimal set")
+	require.Contains(t, phrases(keyphrases), "systems")
+	for i := 1; i < len(keyphrases); i++ {
+		require.LessOrEqual(t, keyphrases[i].Score, keyphrases[i-1].Score)
+	}
+	require.Equal(t, keyphrases, TextRank(rakeAbstract, 0))
+	require.Len(t, TextRank(rakeAbstract, 3), 3)
+
+	require.Empty(t, TextRank("", 5))
+	require.Empty(t, TextRank("It was for him.", 5))
+}
+
+// TestTextRankSherlock finds what "The Five Orange Pips" is about.
+func TestTextRankSherlock(t *testing.T) {
+	data, err := os.ReadFile("../freq/sherlock.txt")
+	require.NoError(t, err)
+	text := string(data)
+	start := indexOf(t, text, "V. THE FIVE ORANGE PIPS")
+	end := indexOf(t, text, "VI. THE MAN WITH THE TWISTED LIP")
+	text = text[start:end]
+
+	keyphrases := phrases(TextRank(text, 25))
+	require.Contains(t, keyphrases[:10], "john openshaw")
+	require.Contains(t, keyphrases[:10], "sherlock holmes")
+	require.Contains(t, keyphrases, "orange pips")
+}
+
+// indexOf returns the index of the last occurrence of substr in s, after
+// the table of contents.
+func indexOf(t *testing.T, s, substr string) int {
+	i := strings.LastIndex(s, substr)
+	require.GreaterOrEqual(t, i, 0, substr)
+	return i
+}
@@ -0,0 +1,217 @@
+package nlp
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Language profiles, langprofiles/<lang>.txt with one n-gram per line, most
+// frequent first, and # comments. Spaces in n-grams are written as _.
+//
+//go:embed langprofiles/*.txt
+var profileFiles embed.FS
+
+// Language detection parameters
+const (
+	profileSize     = 500  // N-grams in a language profile
+	maxNGram        = 3    // Longest n-gram, in runes
+	minDetectGrams  = 10   // Distinct n-grams a text needs to be detected
+	detectTemp      = 0.01 // Softmax temperature turning similarities to confidences
+	minConfidence   = 0.01 // Guesses below are dropped
+	detectMaxLength = 8192 // Bytes of text looked at by DetectLanguage
+)
+
+// LanguageGuess is a candidate language of a text.
+type LanguageGuess struct {
+	Lang       string  `json:"lang"`       // Language code, e.g. "en"
+	Confidence float64 `json:"confidence"` // In [0, 1], the guesses of a text sum to 1
+}
+
+// profile is a ranked n-gram profile, n-gram -> rank (0 is most frequent).
+type profile map[string]int
+
+// profiles returns the built-in language profiles, language code -> profile.
+var profiles = sync.OnceValue(func() map[string]profile {
+	m := make(map[string]profile)
+	for _, lang := range DetectableLanguages() {
+		file, err := profileFiles.Open("langprofiles/" + lang + ".txt")
+		if err != nil {
+			panic(fmt.Sprintf("nlp: can't open %s profile - %s", lang, err))
+		}
+		p, err := readProfile(file)
+		file.Close()
+		if err != nil {
+			panic(fmt.Sprintf("nlp: bad %s profile - %s", lang, err))
+		}
+		m[lang] = p
+	}
+	return m
+})
+
+// DetectableLanguages returns the language codes known to DetectLanguage,
+// sorted.
+func DetectableLanguages() []string {
+	entries, _ := profileFiles.ReadDir("langprofiles")
+	var langs []string
+	for _, e := range entries {
+		langs = append(langs, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// DetectLanguage returns the likely languages of text, most likely first.
+// Languages with a confidence below 1% are left out. It returns nil if text
+// is too short to tell, a few words are usually enough. Only the first 8KB
+// of text are looked at.
+//
+// Detection compares the character n-grams of text with the profiles of
+// the languages in DetectableLanguages, using the "out-of-place" distance of
+// Cavnar & Trenkle's N-Gram-Based Text Categorization.
+func DetectLanguage(text string) []LanguageGuess {
+	if len(text) > detectMaxLength {
+		text = text[:detectMaxLength]
+	}
+	doc := newProfile(text, profileSize)
+	if len(doc) < minDetectGrams {
+		return nil
+	}
+
+	langs := DetectableLanguages()
+	sims := make([]float64, len(langs))
+	best := 0.0
+	for i, lang := range langs {
+		sims[i] = similarity(doc, profiles()[lang])
+		best = max(best, sims[i])
+	}
+
+	// Softmax, shifted by best so exp doesn't overflow
+	total := 0.0
+	for i, sim := range sims {
+		sims[i] = math.Exp((sim - best) / detectTemp)
+		total += sims[i]
+	}
+	var guesses []LanguageGuess
+	for i, lang := range langs {
+		if c := sims[i] / total; c >= minConfidence {
+			guesses = append(guesses, LanguageGuess{Lang: lang, Confidence: c})
+		}
+	}
+	sort.Slice(guesses, func(i, j int) bool {
+		if guesses[i].Confidence != guesses[j].Confidence {
+			return guesses[i].Confidence > guesses[j].Confidence
+		}
+		return guesses[i].Lang < guesses[j].Lang
+	})
+	return guesses
+}
+
+// similarity returns how close the document profile doc is to the language
+// profile lang, from 0 (nothing in common) to 1 (same ranks). N-grams
+// missing from lang count as maximally out of place.
+func similarity(doc, lang profile) float64 {
+	dist := 0
+	for gram, rank := range doc {
+		if r, ok := lang[gram]; ok {
+			dist += min(abs(rank-r), profileSize)
+		} else {
+			dist += profileSize
+		}
+	}
+	return 1 - float64(dist)/float64(len(doc)*profileSize)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// newProfile returns the profile of the size most frequent n-grams of text.
+func newProfile(text string, size int) profile {
+	grams := rankNGrams(text)
+	if len(grams) > size {
+		grams = grams[:size]
+	}
+	p := make(profile, len(grams))
+	for i, gram := range grams {
+		p[gram] = i
+	}
+	return p
+}
+
+// rankNGrams returns the 1 to maxNGram rune n-grams of the words of text,
+// most frequent first, ties in n-gram order. Words are case folded and
+// padded with a space on each side, so "the" gives "t", "h", "e", " t",
+// "th", "he", "e ", " th", "the" and "he ".
+func rankNGrams(text string) []string {
+	counts := make(map[string]int)
+	for _, word := range strings.FieldsFunc(Fold(text), isNotLetter) {
+		runes := []rune(" " + word + " ")
+		for i := range runes {
+			for n := 1; n <= maxNGram && i+n <= len(runes); n++ {
+				if n == 1 && runes[i] == ' ' {
+					continue
+				}
+				counts[string(runes[i:i+n])]++
+			}
+		}
+	}
+
+	grams := make([]string, 0, len(counts))
+	for gram := range counts {
+		grams = append(grams, gram)
+	}
+	sort.Slice(grams, func(i, j int) bool {
+		if counts[grams[i]] != counts[grams[j]] {
+			return counts[grams[i]] > counts[grams[j]]
+		}
+		return grams[i] < grams[j]
+	})
+	return grams
+}
+
+// isNotLetter reports whether r separates words for n-grams, marks are part
+// of words since many scripts (e.g. Devanagari) write vowels with them.
+func isNotLetter(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsMark(r)
+}
+
+// writeProfile writes the profile of text in the format of langprofiles.
+func writeProfile(w io.Writer, text, source string) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# Generated from %s, do not edit\n", source)
+	grams := rankNGrams(text)
+	if len(grams) > profileSize {
+		grams = grams[:profileSize]
+	}
+	for _, gram := range grams {
+		fmt.Fprintln(bw, strings.ReplaceAll(gram, " ", "_"))
+	}
+	return bw.Flush()
+}
+
+func readProfile(r io.Reader) (profile, error) {
+	p := make(profile)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p[strings.ReplaceAll(line, "_", " ")] = len(p)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,91 @@
+package nlp
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateProfiles = flag.Bool("update-profiles", false, "regenerate langprofiles from testdata/lang")
+
+// TestLanguageProfiles checks that the built-in profiles are the ones
+// generated from testdata/lang, run with -update-profiles to regenerate them.
+func TestLanguageProfiles(t *testing.T) {
+	files, err := filepath.Glob("testdata/lang/*.txt")
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(files), 20)
+
+	var langs []string
+	for _, file := range files {
+		lang := strings.TrimSuffix(filepath.Base(file), ".txt")
+		langs = append(langs, lang)
+		text, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, writeProfile(&buf, string(text), "testdata/lang/"+lang+".txt"))
+		out := filepath.Join("langprofiles", lang+".txt")
+		if *updateProfiles {
+			require.NoError(t, os.WriteFile(out, buf.Bytes(), 0o644))
+			continue
+		}
+		data, err := profileFiles.ReadFile(out)
+		require.NoError(t, err, lang)
+		require.Equal(t, string(data), buf.String(), "stale %s profile, run go test -run TestLanguageProfiles -update-profiles", lang)
+	}
+	if !*updateProfiles {
+		require.Equal(t, langs, DetectableLanguages())
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	file, err := os.Open("testdata/lang_test.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	s := bufio.NewScanner(file)
+	n := 0
+	for s.Scan() {
+		line := s.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lang, text, ok := strings.Cut(line, "\t")
+		require.True(t, ok, line)
+
+		guesses := DetectLanguage(text)
+		require.NotEmpty(t, guesses, text)
+		require.Equal(t, lang, guesses[0].Lang, "%s: %v", text, guesses)
+		n++
+	}
+	require.NoError(t, s.Err())
+	require.Equal(t, len(DetectableLanguages())*2, n)
+}
+
+func TestDetectLanguageConfidence(t *testing.T) {
+	guesses := DetectLanguage("Sherlock Holmes took his bottle from the corner of the mantelpiece")
+	require.Equal(t, "en", guesses[0].Lang)
+	require.Greater(t, guesses[0].Confidence, 0.9)
+
+	total := 0.0
+	for i, g := range guesses {
+		require.GreaterOrEqual(t, g.Confidence, minConfidence)
+		if i > 0 {
+			require.LessOrEqual(t, g.Confidence, guesses[i-1].Confidence)
+		}
+		total += g.Confidence
+	}
+	require.LessOrEqual(t, total, 1.0+1e-9)
+}
+
+func TestDetectLanguageShort(t *testing.T) {
+	for _, text := range []string{"", "42 + 17 = 59", "ok", "?!"} {
+		require.Nil(t, DetectLanguage(text), text)
+	}
+}
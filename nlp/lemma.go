@@ -0,0 +1,173 @@
+package nlp
+
+import (
+	"bufio"
+	_ "embed"
+	"strings"
+	"sync"
+
+	"github.com/ArditZubaku/nlp/stemmer"
+)
+
+// English lemmas of irregular forms, one "form lemma" per line and # comments
+//
+//go:embed lemmas/en.txt
+var lemmaFile string
+
+// lemmas returns the dictionary of lemmaFile, form -> lemma.
+var lemmas = sync.OnceValue(func() map[string]string {
+	m := make(map[string]string)
+	s := bufio.NewScanner(strings.NewReader(lemmaFile))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if form, lemma, ok := strings.Cut(line, " "); ok {
+			m[form] = lemma
+		}
+	}
+	return m
+})
+
+// Lemma returns the dictionary form of an English word, which must be lower
+// case: "went" -> "go", "mice" -> "mouse", "running" -> "run". Unlike a stem,
+// a lemma is a real word, fit to show users. Irregular forms come from a
+// dictionary, regular plurals and verb forms are undone with rules. There is
+// no part of speech, so "left" is always "leave".
+func Lemma(word string) string {
+	word = trimPossessive(word)
+	if lemma, ok := lemmas()[word]; ok {
+		return lemma
+	}
+	if !isLowerASCII(word) {
+		return word
+	}
+
+	n := len(word)
+	switch {
+	case strings.HasSuffix(word, "ies") || strings.HasSuffix(word, "ied"):
+		if n > 4 {
+			return word[:n-3] + "y" // studies, studied -> study
+		}
+		return word[:n-1] // ties, tied -> tie
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"),
+		strings.HasSuffix(word, "zzes"):
+		return word[:n-2]
+	case strings.HasSuffix(word, "s"):
+		if n > 3 && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is") {
+			return word[:n-1]
+		}
+	case strings.HasSuffix(word, "ing"):
+		return unsuffix(word, word[:n-3])
+	case strings.HasSuffix(word, "ed") && !strings.HasSuffix(word, "eed"):
+		return unsuffix(word, word[:n-2])
+	}
+	return word
+}
+
+// unsuffix returns the verb of word without its "ing" or "ed" suffix, stem,
+// restoring a doubled consonant ("running") or a dropped e ("hoping").
+func unsuffix(word, stem string) string {
+	n := len(stem)
+	if n < 3 || !strings.ContainsAny(stem, "aeiouy") {
+		return word // sing, bred
+	}
+
+	last := stem[n-1]
+	switch {
+	case last == stem[n-2] && strings.IndexByte("bdgmnprt", last) >= 0:
+		// A doubled consonant after a single vowel: runn, stopp, but not add
+		if n > 3 && isVowel(stem[n-3]) && !isVowel(stem[n-4]) {
+			return stem[:n-1]
+		}
+	case needsE(stem):
+		return stem + "e"
+	}
+	return stem
+}
+
+// eEndings are endings of verb stems that lost a final e, the ones after a
+// single vowel are in eAfterVowel: conclude, desire, define, describe ...
+var (
+	eEndings    = []string{"iz", "dg", "rg", "lg", "eng", "ung", "u", "v", "c"}
+	eAfterVowel = []string{"ud", "ut", "ur", "um", "ir", "in", "ib", "ap", "ar", "ok", "od", "id", "ot"}
+)
+
+// needsE reports whether a verb stem lost a final e before "ing" or "ed".
+func needsE(stem string) bool {
+	n := len(stem)
+	last := stem[n-1]
+	for _, suffix := range eEndings {
+		if strings.HasSuffix(stem, suffix) {
+			return true
+		}
+	}
+	for _, suffix := range eAfterVowel {
+		if strings.HasSuffix(stem, suffix) && !isVowel(stem[n-3]) {
+			return true
+		}
+	}
+	switch {
+	case last == 'l' && strings.IndexByte("bcdfgkptz", stem[n-2]) >= 0:
+		return true // troubl, dangl
+	case last == 't' && stem[n-2] == 'a' && strings.IndexByte("aeo", stem[n-3]) < 0:
+		return true // isolat, situat, but not treat, float
+	case (last == 's' || last == 'z' || last == 'g') && isVowel(stem[n-2]):
+		return true // promis, seiz, engag
+	}
+
+	// A single syllable ending with consonant-vowel-consonant: hop, mak
+	if strings.ContainsRune("wxy", rune(last)) || isVowel(last) || !isVowel(stem[n-2]) || isVowel(stem[n-3]) {
+		return false
+	}
+	return vowelGroups(stem) == 1
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) >= 0
+}
+
+// vowelGroups returns the number of runs of vowels in s, roughly its
+// syllables.
+func vowelGroups(s string) int {
+	groups := 0
+	for i := range len(s) {
+		if isVowel(s[i]) && (i == 0 || !isVowel(s[i-1])) {
+			groups++
+		}
+	}
+	return groups
+}
+
+// trimPossessive removes a possessive "'s" or "'" from word.
+func trimPossessive(word string) string {
+	for _, suffix := range []string{"'s", "’s", "'", "’"} {
+		if w, ok := strings.CutSuffix(word, suffix); ok && w != "" {
+			return w
+		}
+	}
+	return word
+}
+
+func isLowerASCII(s string) bool {
+	for i := range len(s) {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// LemmaFilter replaces token terms with their English lemma, see Lemma.
+// Use it after Lowercase, instead of StemFilter.
+func LemmaFilter() TokenFilter {
+	return StemFilter(stemmer.Func(Lemma))
+}
+
+// WithLemmas replaces the terms of Tokenize by their English lemma (see
+// Lemma) instead of stemming them.
+func WithLemmas() Option {
+	return WithStemmer(stemmer.Func(Lemma))
+}
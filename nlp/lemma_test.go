@@ -0,0 +1,58 @@
+package nlp
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLemma(t *testing.T) {
+	file, err := os.Open("testdata/lemmas_en.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		require.Len(t, fields, 2, line)
+		require.Equal(t, fields[1], Lemma(fields[0]), fields[0])
+	}
+	require.NoError(t, s.Err())
+}
+
+func TestLemmaFile(t *testing.T) {
+	// Every line is "form lemma", with no form listed twice
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(lemmaFile), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		require.Len(t, fields, 2, line)
+		require.False(t, seen[fields[0]], "duplicate %q", fields[0])
+		seen[fields[0]] = true
+	}
+	require.Equal(t, len(seen), len(lemmas()))
+}
+
+func TestLemmaAnalyzer(t *testing.T) {
+	text := "The mice were running and the geese went swimming."
+	require.Equal(t,
+		[]string{"the", "mouse", "be", "run", "and", "the", "goose", "go", "swim"},
+		Tokenize(text, WithLemmas()),
+	)
+
+	a, ok := Preset("lemma")
+	require.True(t, ok)
+	tokens := a.Analyze("Mice ran")
+	require.Equal(t, "mouse", tokens[0].Stem)
+	require.Equal(t, "mice", tokens[0].Normalized)
+	require.Equal(t, []string{"go"}, NewAnalyzer(Lowercase(), LemmaFilter()).Terms("WENT"))
+}
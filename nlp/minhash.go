@@ -0,0 +1,318 @@
+package nlp
+
+import (
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// Near-duplicate detection parameters
+const (
+	dupShingleSize = 3   // Words per shingle of a document
+	numMinHashes   = 128 // Length of the signatures of MinHashIndex, estimates are within about 1/sqrt(128) = 0.09
+	lshRecall      = 0.95
+)
+
+// Duplicate is a document similar to another one.
+type Duplicate struct {
+	ID         string  `json:"id"`
+	Similarity float64 `json:"similarity"` // From 0 to 1 (identical)
+}
+
+// Jaccard returns the Jaccard similarity of two sets: the size of their
+// intersection divided by the size of their union, 0 if both are empty.
+func Jaccard(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	union, shared := len(set), 0
+	seen := make(map[string]bool, len(b))
+	for _, s := range b {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		if set[s] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+// Signature is a MinHash signature of a set.
+type Signature []uint64
+
+// MinHash returns the MinHash signature of the set of shingles, with n hash
+// functions: for each the smallest hash of the shingles. The probability that
+// two signatures agree at a position is the Jaccard similarity of their sets,
+// so signatures estimate similarity in constant space. It returns nil if
+// there are no shingles.
+func MinHash(shingles []string, n int) Signature {
+	if len(shingles) == 0 || n <= 0 {
+		return nil
+	}
+
+	seeds := make([]uint64, n)
+	for i := range seeds {
+		seeds[i] = mix64(uint64(i+1) * 0x9e3779b97f4a7c15)
+	}
+	sig := make(Signature, n)
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for _, s := range shingles {
+		h := hashString(s)
+		for i, seed := range seeds {
+			sig[i] = min(sig[i], mix64(h^seed))
+		}
+	}
+	return sig
+}
+
+// Similarity returns the estimated Jaccard similarity of the sets of s and
+// other, 0 if they have different lengths.
+func (s Signature) Similarity(other Signature) float64 {
+	if len(s) == 0 || len(s) != len(other) {
+		return 0
+	}
+	equal := 0
+	for i := range s {
+		if s[i] == other[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(s))
+}
+
+// MinHashIndex finds near-duplicate documents, whose word shingles have a
+// Jaccard similarity of at least a threshold, with MinHash signatures and
+// locality-sensitive hashing: signatures are cut into bands and documents
+// sharing a band are candidates, so a lookup doesn't compare a document with
+// all the others. Candidates are then checked with their signatures.
+//
+// A MinHashIndex is not safe for concurrent use while documents are added.
+type MinHashIndex struct {
+	threshold float64
+	rows      int                   // Rows of a band
+	buckets   []map[uint64][]string // Band -> hash of the band -> document IDs
+	sigs      map[string]Signature  // Document ID -> signature, nil for texts without words
+	ids       []string              // In order of addition
+}
+
+// NewMinHashIndex returns an empty index of documents similar from
+// threshold, between 0 and 1 (0.8 is a common choice). The bands are chosen
+// so that documents with a similarity of threshold are found 95% of the time.
+func NewMinHashIndex(threshold float64) *MinHashIndex {
+	rows := lshRows(threshold)
+	buckets := make([]map[uint64][]string, numMinHashes/rows)
+	for i := range buckets {
+		buckets[i] = make(map[uint64][]string)
+	}
+	return &MinHashIndex{
+		threshold: threshold,
+		rows:      rows,
+		buckets:   buckets,
+		sigs:      make(map[string]Signature),
+	}
+}
+
+// lshRows returns the most rows of the bands of numMinHashes signatures
+// (more rows mean fewer false candidates) such that two documents with a
+// similarity of threshold share a band with a probability of lshRecall.
+// That probability is 1 - (1 - threshold^rows)^bands.
+func lshRows(threshold float64) int {
+	rows := 1
+	for r := 2; r <= numMinHashes; r++ {
+		bands := numMinHashes / r
+		if 1-math.Pow(1-math.Pow(threshold, float64(r)), float64(bands)) >= lshRecall {
+			rows = r
+		}
+	}
+	return rows
+}
+
+// Add adds document id with text and reports whether id was missing, text
+// is ignored if not. A text without words is never a duplicate.
+func (m *MinHashIndex) Add(id, text string) bool {
+	if _, ok := m.sigs[id]; ok {
+		return false
+	}
+
+	sig := MinHash(documentShingles(text), numMinHashes)
+	m.sigs[id] = sig
+	m.ids = append(m.ids, id)
+	if sig == nil {
+		return true
+	}
+	for band, key := range m.bandKeys(sig) {
+		m.buckets[band][key] = append(m.buckets[band][key], id)
+	}
+	return true
+}
+
+// Len returns the number of documents.
+func (m *MinHashIndex) Len() int {
+	return len(m.ids)
+}
+
+// Similar returns the documents similar to text, most similar first.
+func (m *MinHashIndex) Similar(text string) []Duplicate {
+	sig := MinHash(documentShingles(text), numMinHashes)
+	if sig == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var dups []Duplicate
+	for band, key := range m.bandKeys(sig) {
+		for _, id := range m.buckets[band][key] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if sim := sig.Similarity(m.sigs[id]); sim >= m.threshold {
+				dups = append(dups, Duplicate{ID: id, Similarity: sim})
+			}
+		}
+	}
+	sortDuplicates(dups)
+	return dups
+}
+
+// Clusters returns the groups of similar documents, transitively: if a is
+// similar to b and b to c, a, b and c are a cluster. Documents in a cluster
+// are sorted, clusters by their first document. Documents without
+// duplicates are left out.
+func (m *MinHashIndex) Clusters() [][]string {
+	ds := newDisjointSets()
+	checked := make(map[[2]string]bool)
+	for _, band := range m.buckets {
+		for _, ids := range band {
+			for i, a := range ids {
+				for _, b := range ids[i+1:] {
+					pair := [2]string{a, b} // IDs are in order of addition in all bands
+					if checked[pair] {
+						continue
+					}
+					checked[pair] = true
+					if m.sigs[a].Similarity(m.sigs[b]) >= m.threshold {
+						ds.union(a, b)
+					}
+				}
+			}
+		}
+	}
+	return ds.sets()
+}
+
+// bandKeys returns the hashes of the bands of sig.
+func (m *MinHashIndex) bandKeys(sig Signature) []uint64 {
+	keys := make([]uint64, len(m.buckets))
+	for band := range keys {
+		key := uint64(0)
+		for _, v := range sig[band*m.rows : (band+1)*m.rows] {
+			key = mix64(key ^ v)
+		}
+		keys[band] = key
+	}
+	return keys
+}
+
+// documentShingles returns the case folded word shingles of text.
+func documentShingles(text string) []string {
+	return WordShingles(NewAnalyzer(Lowercase()).Terms(text), dupShingleSize)
+}
+
+// hashString returns the 64-bit FNV-1a hash of s.
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// mix64 scrambles the bits of x (the finalizer of SplitMix64), deriving
+// independent hash functions from one hash.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// sortDuplicates sorts dups most similar first, then by ID.
+func sortDuplicates(dups []Duplicate) {
+	sort.Slice(dups, func(i, j int) bool {
+		if dups[i].Similarity != dups[j].Similarity {
+			return dups[i].Similarity > dups[j].Similarity
+		}
+		return dups[i].ID < dups[j].ID
+	})
+}
+
+// disjointSets is a union-find of IDs, grouping duplicates into clusters.
+type disjointSets struct {
+	parent map[string]string
+}
+
+func newDisjointSets() *disjointSets {
+	return &disjointSets{parent: make(map[string]string)}
+}
+
+// find returns the representative of the set of id.
+func (ds *disjointSets) find(id string) string {
+	root := id
+	for {
+		p, ok := ds.parent[root]
+		if !ok || p == root {
+			break
+		}
+		root = p
+	}
+	for id != root { // Path compression
+		next := ds.parent[id]
+		ds.parent[id] = root
+		id = next
+	}
+	return root
+}
+
+// union merges the sets of a and b.
+func (ds *disjointSets) union(a, b string) {
+	ra, rb := ds.find(a), ds.find(b)
+	if ra == rb {
+		return
+	}
+	if rb < ra {
+		ra, rb = rb, ra
+	}
+	ds.parent[ra] = ra
+	ds.parent[rb] = ra
+}
+
+// sets returns the sets of more than one ID, each sorted, sorted by their
+// first ID.
+func (ds *disjointSets) sets() [][]string {
+	groups := make(map[string][]string)
+	for id := range ds.parent {
+		root := ds.find(id)
+		groups[root] = append(groups[root], id)
+	}
+
+	var sets [][]string
+	for _, ids := range groups {
+		if len(ids) > 1 {
+			sort.Strings(ids)
+			sets = append(sets, ids)
+		}
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i][0] < sets[j][0] })
+	return sets
+}
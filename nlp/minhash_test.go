@@ -0,0 +1,95 @@
+package nlp
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJaccard(t *testing.T) {
+	require.Equal(t, 0.5, Jaccard([]string{"a", "b", "c"}, []string{"b", "c", "d", "d"}))
+	require.Equal(t, 1.0, Jaccard([]string{"a"}, []string{"a"}))
+	require.Equal(t, 0.0, Jaccard(nil, []string{"a"}))
+	require.Equal(t, 0.0, Jaccard(nil, nil))
+}
+
+func TestMinHash(t *testing.T) {
+	a := Shingles("The quick brown fox jumps over the lazy dog", 3)
+	b := Shingles("The quick brown fox jumped over the lazy dog", 3)
+	c := Shingles("Colorless green ideas sleep furiously", 3)
+
+	sig := MinHash(a, 256)
+	require.Len(t, sig, 256)
+	require.Equal(t, sig, MinHash(a, 256))
+	require.Equal(t, 1.0, sig.Similarity(MinHash(a, 256)))
+	require.InDelta(t, Jaccard(a, b), sig.Similarity(MinHash(b, 256)), 0.1)
+	require.InDelta(t, Jaccard(a, c), sig.Similarity(MinHash(c, 256)), 0.1)
+
+	require.Nil(t, MinHash(nil, 256))
+	require.Equal(t, 0.0, sig.Similarity(MinHash(a, 128)))
+}
+
+func TestLSHRows(t *testing.T) {
+	// Probability that documents with similarity s share a band
+	candidate := func(s float64, rows int) float64 {
+		bands := numMinHashes / rows
+		return 1 - math.Pow(1-math.Pow(s, float64(rows)), float64(bands))
+	}
+	for _, threshold := range []float64{0.5, 0.8, 0.9} {
+		rows := lshRows(threshold)
+		require.GreaterOrEqual(t, candidate(threshold, rows), lshRecall, "threshold %v", threshold)
+		require.Less(t, candidate(threshold-0.5, rows), 0.3, "threshold %v", threshold)
+	}
+	require.Equal(t, numMinHashes, lshRows(1))
+	require.Equal(t, 1, lshRows(0))
+}
+
+// nearDuplicates returns the paragraphs of Sherlock Holmes with more than 80
+// words and edited copies of three of them, with the expected clusters.
+func nearDuplicates(t *testing.T) (map[string]string, [][]string) {
+	data, err := os.ReadFile("../freq/sherlock.txt")
+	require.NoError(t, err)
+
+	docs := make(map[string]string)
+	i := 0
+	for _, p := range strings.Split(string(data), "\n\n") {
+		if len(strings.Fields(p)) > 80 {
+			docs[fmt.Sprintf("p%03d", i)] = p
+			i++
+		}
+	}
+
+	// Copies with a few words changed, upper case, and the end cut
+	docs["p010-copy"] = strings.Replace(docs["p010"], "the", "a", 2)
+	docs["p050-copy"] = strings.ToUpper(docs["p050"]) + " (reprinted)"
+	words := strings.Fields(docs["p100"])
+	docs["p100-copy"] = strings.Join(words[:len(words)*9/10], " ")
+	clusters := [][]string{{"p010", "p010-copy"}, {"p050", "p050-copy"}, {"p100", "p100-copy"}}
+	return docs, clusters
+}
+
+func TestMinHashIndex(t *testing.T) {
+	docs, clusters := nearDuplicates(t)
+	m := NewMinHashIndex(0.8)
+	for id, text := range docs {
+		require.True(t, m.Add(id, text))
+	}
+	require.False(t, m.Add("p010", "ignored"))
+	require.True(t, m.Add("empty", "..."))
+	require.True(t, m.Add("empty2", ""))
+	require.Equal(t, len(docs)+2, m.Len())
+
+	require.Equal(t, clusters, m.Clusters())
+
+	similar := m.Similar(docs["p010"])
+	require.Len(t, similar, 2)
+	require.Equal(t, Duplicate{ID: "p010", Similarity: 1}, similar[0])
+	require.Equal(t, "p010-copy", similar[1].ID)
+	require.Greater(t, similar[1].Similarity, 0.8)
+	require.Empty(t, m.Similar("Colorless green ideas sleep furiously"))
+	require.Empty(t, m.Similar(""))
+}
@@ -0,0 +1,157 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// NGrams returns the word n-grams of tokens, in order.
+// NGrams([a b c], 2) is [[a b] [b c]].
+func NGrams(tokens []string, n int) [][]string {
+	if n <= 0 || n > len(tokens) {
+		return nil
+	}
+
+	ngrams := make([][]string, 0, len(tokens)-n+1)
+	for i := 0; i+n <= len(tokens); i++ {
+		ngrams = append(ngrams, tokens[i:i+n:i+n]) // Capacity limited, append won't overwrite tokens
+	}
+	return ngrams
+}
+
+// Shingles returns the distinct character k-shingles (runs of k runes) of
+// text, in order of first appearance. The text is case folded and runs of
+// spaces become a single space first, so formatting does not change the
+// shingles. Text shorter than k is a single shingle.
+func Shingles(text string, k int) []string {
+	if k <= 0 {
+		return nil
+	}
+
+	runes := []rune(strings.Join(strings.FieldsFunc(Fold(text), unicode.IsSpace), " "))
+	if len(runes) == 0 {
+		return nil
+	}
+	if len(runes) <= k {
+		return []string{string(runes)}
+	}
+
+	var shingles []string
+	seen := make(map[string]bool)
+	for i := 0; i+k <= len(runes); i++ {
+		s := string(runes[i : i+k])
+		if !seen[s] {
+			seen[s] = true
+			shingles = append(shingles, s)
+		}
+	}
+	return shingles
+}
+
+// WordShingles returns the distinct word k-shingles of tokens, each the
+// words of an n-gram joined by a space, in order of first appearance. Fewer
+// than k tokens are a single shingle.
+func WordShingles(tokens []string, k int) []string {
+	if k <= 0 || len(tokens) == 0 {
+		return nil
+	}
+	if len(tokens) <= k {
+		return []string{strings.Join(tokens, " ")}
+	}
+
+	var shingles []string
+	seen := make(map[string]bool)
+	for _, ngram := range NGrams(tokens, k) {
+		s := strings.Join(ngram, " ")
+		if !seen[s] {
+			seen[s] = true
+			shingles = append(shingles, s)
+		}
+	}
+	return shingles
+}
+
+// Collocation is a bigram that occurs more often than chance.
+type Collocation struct {
+	Words [2]string
+	Count int     // Occurrences of the bigram
+	PMI   float64 // Pointwise mutual information, in bits
+	LLR   float64 // Dunning's log-likelihood ratio
+}
+
+// String returns the words of the collocation joined by a space.
+func (c Collocation) String() string {
+	return c.Words[0] + " " + c.Words[1]
+}
+
+// Collocations scores the bigrams of tokens that occur at least minCount
+// times, sorted by decreasing log-likelihood ratio, which unlike PMI does not
+// favor rare bigrams. Only tokens at consecutive positions form bigrams, so
+// stop words removed by an Analyzer do not create false bigrams:
+//
+//	a, _ := Preset("search")
+//	for _, c := range Collocations(a.Analyze(text), 5)[:10] {
+//		fmt.Println(c) // e.g. "baker street"
+//	}
+func Collocations(tokens []Token, minCount int) []Collocation {
+	type bigram [2]string
+	var (
+		counts = make(map[bigram]int)
+		first  = make(map[string]int) // Count as the first word of a bigram
+		second = make(map[string]int)
+		n      = 0
+	)
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].Position != tokens[i-1].Position+1 {
+			continue
+		}
+		b := bigram{tokens[i-1].Stem, tokens[i].Stem}
+		counts[b]++
+		first[b[0]]++
+		second[b[1]]++
+		n++
+	}
+
+	var collocations []Collocation
+	for b, count := range counts {
+		if count < minCount {
+			continue
+		}
+
+		// Contingency table of the bigram: k[0][0] is w1 w2, k[0][1] is w1
+		// followed by another word ...
+		c1, c2 := first[b[0]], second[b[1]]
+		k := [2][2]float64{
+			{float64(count), float64(c1 - count)},
+			{float64(c2 - count), float64(n - c1 - c2 + count)},
+		}
+		rows := [2]float64{float64(c1), float64(n - c1)}
+		cols := [2]float64{float64(c2), float64(n - c2)}
+		llr := 0.0
+		for i := range 2 {
+			for j := range 2 {
+				if k[i][j] > 0 {
+					llr += k[i][j] * math.Log(k[i][j]*float64(n)/(rows[i]*cols[j]))
+				}
+			}
+		}
+
+		collocations = append(collocations, Collocation{
+			Words: b,
+			Count: count,
+			PMI:   math.Log2(float64(count) * float64(n) / (float64(c1) * float64(c2))),
+			LLR:   2 * llr,
+		})
+	}
+
+	sort.Slice(collocations, func(i, j int) bool {
+		ci, cj := collocations[i], collocations[j]
+		if ci.LLR != cj.LLR {
+			return ci.LLR > cj.LLR
+		}
+		return ci.String() < cj.String()
+	})
+	return collocations
+}
@@ -0,0 +1,55 @@
+package nlp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNGrams(t *testing.T) {
+	tokens := []string{"who", "on", "first", "base"}
+	require.Equal(t, [][]string{{"who", "on"}, {"on", "first"}, {"first", "base"}}, NGrams(tokens, 2))
+	require.Equal(t, [][]string{{"who", "on", "first", "base"}}, NGrams(tokens, 4))
+	require.Nil(t, NGrams(tokens, 5))
+	require.Nil(t, NGrams(tokens, 0))
+
+	// Appending to an n-gram must not change tokens
+	bigrams := NGrams(tokens, 2)
+	_ = append(bigrams[0], "x")
+	require.Equal(t, "first", tokens[2])
+}
+
+func TestShingles(t *testing.T) {
+	require.Equal(t, []string{"abc", "bcd", "cd ", "d a", " ab"}, Shingles("ABCD  abc", 3))
+	require.Equal(t, []string{"ab"}, Shingles("ab", 3))
+	require.Nil(t, Shingles("  ", 3))
+	require.Equal(t, Shingles("Naïve  café", 4), Shingles("naïve\ncafé", 4))
+}
+
+func TestWordShingles(t *testing.T) {
+	tokens := []string{"a", "rose", "is", "a", "rose", "is", "a", "rose"}
+	require.Equal(t, []string{"a rose is", "rose is a", "is a rose"}, WordShingles(tokens, 3))
+	require.Equal(t, []string{"a rose"}, WordShingles(tokens[:2], 3))
+	require.Nil(t, WordShingles(nil, 3))
+	require.Nil(t, WordShingles(tokens, 0))
+}
+
+func TestCollocations(t *testing.T) {
+	text := strings.Repeat("Holmes walked along Baker Street to see the man. ", 3) +
+		"The man stood in Baker Street waiting. A street, a man, a baker and a walk."
+	a, _ := Preset("search")
+	collocations := Collocations(a.Analyze(text), 2)
+
+	require.NotEmpty(t, collocations)
+	require.Equal(t, "baker street", collocations[0].String())
+	require.Equal(t, 4, collocations[0].Count)
+	require.Greater(t, collocations[0].PMI, 0.0)
+
+	for _, c := range collocations {
+		require.GreaterOrEqual(t, c.Count, 2)
+		// "see the man" has a stop word between see and man
+		require.NotEqual(t, "see man", c.String())
+		require.NotContains(t, c.Words, "the")
+	}
+}
@@ -2,23 +2,65 @@ package nlp
 
 import (
 	"github.com/ArditZubaku/nlp/stemmer"
-	"regexp"
-	"strings"
 )
 
-var (
-	wordRe = regexp.MustCompile(`[a-zA-Z]+`)
-)
+// Option configures Tokenize.
+type Option func(*options)
+
+type options struct {
+	stemmer  stemmer.Stemmer // nil means no stemming
+	analyzer *Analyzer       // Overrides stemmer
+}
+
+// newAnalyzer returns the analyzer for the options, by default the "standard"
+// preset with o.stemmer.
+func (o options) newAnalyzer() *Analyzer {
+	if o.analyzer != nil {
+		return o.analyzer
+	}
+	a := NewAnalyzer(Lowercase())
+	if o.stemmer != nil {
+		a.Filters = append(a.Filters, StemFilter(o.stemmer))
+	}
+	return a
+}
+
+// WithLanguage stems tokens with the stemmer registered for lang (e.g. "de").
+// Tokens of a language without a stemmer are not stemmed.
+func WithLanguage(lang string) Option {
+	return func(o *options) {
+		o.stemmer, _ = stemmer.ForLanguage(lang)
+	}
+}
 
-func Tokenize(text string) []string {
-	words := wordRe.FindAllString(text, -1)
-	var tokens []string
-	for _, w := range words {
-		token := strings.ToLower(w)
-		token = stemmer.Stem(token)
-		if len(token) != 0 {
-			tokens = append(tokens, token)
-		}
+// WithStemmer stems tokens with s, nil disables stemming.
+func WithStemmer(s stemmer.Stemmer) Option {
+	return func(o *options) {
+		o.stemmer = s
 	}
-	return tokens
+}
+
+// WithAnalyzer analyzes text with a, the stemmer options are then ignored.
+func WithAnalyzer(a *Analyzer) Option {
+	return func(o *options) {
+		o.analyzer = a
+	}
+}
+
+// newOptions returns the options for opts.
+func newOptions(opts []Option) options {
+	o := options{stemmer: stemmer.Func(stemmer.Stem)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Tokenize splits text into case folded, stemmed tokens.
+// Words are found with the Unicode word boundary rules (UAX #29), so
+// accented letters, non-Latin scripts and numbers are kept, and each
+// Chinese or Japanese ideograph is a token of its own.
+// By default it uses the "standard" preset Analyzer, with the English stemmer.
+func Tokenize(text string, opts ...Option) []string {
+	return newOptions(opts).newAnalyzer().Terms(text)
 }
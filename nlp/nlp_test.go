@@ -3,8 +3,8 @@ package nlp
 import (
 	"fmt"
 	"os"
-	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/BurntSushi/toml"
 	"github.com/stretchr/testify/require"
@@ -13,9 +13,18 @@ import (
 type testCase struct {
 	// When we have to do serialization, fields HAVE TO be exported
 	Text   string
+	Lang   string // Empty for the default (English)
 	Tokens []string
 }
 
+// tokenize tokenizes tc.Text for tc.Lang.
+func (tc testCase) tokenize() []string {
+	if tc.Lang == "" {
+		return Tokenize(tc.Text)
+	}
+	return Tokenize(tc.Text, WithLanguage(tc.Lang))
+}
+
 var tokenizeCases = []struct {
 	text   string
 	tokens []string
@@ -79,7 +88,7 @@ func TestTokenizeTable(t *testing.T) {
 	for _, tc := range loadTokenizeCases(t) {
 		// Pick a name for the test
 		t.Run(tc.Text, func(t *testing.T) {
-			tokens := Tokenize(tc.Text)
+			tokens := tc.tokenize()
 			// NOTE: TOML doesn't have nil
 			if tokens == nil {
 				tokens = []string{}
@@ -91,7 +100,7 @@ func TestTokenizeTable(t *testing.T) {
 	for _, tc := range loadTokenizeCasesV2(t) {
 		// Pick a name for the test
 		t.Run(tc.Text, func(t *testing.T) {
-			tokens := Tokenize(tc.Text)
+			tokens := tc.tokenize()
 			// NOTE: TOML doesn't have nil
 			if tokens == nil {
 				tokens = []string{}
@@ -116,13 +125,32 @@ func TestTokenize(t *testing.T) {
 }
 
 func FuzzTokenize(f *testing.F) {
+	f.Add("Who's on first? He was lying, dying and happy.")
+	f.Add("Die Käufer kaufen ΣΊΣΥΦΟΣ straße")
 	f.Fuzz(func(t *testing.T, text string) {
-		tokens := Tokenize(text)
-		ltext := strings.ToLower(text)
-		for _, tok := range tokens {
-			if !strings.Contains(ltext, tok) {
-				t.Fatal(tok)
-			}
+		tokens := TokenizeDetailed(text)
+		terms := Tokenize(text)
+		require.Len(t, terms, len(tokens))
+		for i, tok := range tokens {
+			// Stems may not appear in the text (dying -> die), the tokens do
+			require.Equal(t, tok.Text, text[tok.Start:tok.End])
+			require.Equal(t, utf8.RuneCountInString(text[:tok.Start]), tok.RuneStart)
+			require.Equal(t, utf8.RuneCountInString(tok.Text), tok.RuneEnd-tok.RuneStart)
+			require.Equal(t, Fold(tok.Text), tok.Normalized)
+			require.Equal(t, terms[i], tok.Stem)
 		}
 	})
 }
+
+func TestTokenizeOptions(t *testing.T) {
+	text := "Die Käufer kaufen Katzen"
+
+	tokens := Tokenize(text, WithLanguage("de"))
+	require.Equal(t, []string{"die", "kauf", "kauf", "katz"}, tokens)
+
+	tokens = Tokenize(text, WithLanguage("xx"))
+	require.Equal(t, []string{"die", "käufer", "kaufen", "katzen"}, tokens)
+
+	tokens = Tokenize(text, WithStemmer(nil))
+	require.Equal(t, []string{"die", "käufer", "kaufen", "katzen"}, tokens)
+}
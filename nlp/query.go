@@ -0,0 +1,341 @@
+package nlp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A query is a tree of nodes, matched against an index. match returns the
+// matching documents with their score, and false if the node is ignored,
+// e.g. a word that is a stop word.
+type queryNode interface {
+	match(ix *Index) (map[string]float64, bool)
+}
+
+// phraseQuery matches documents with the terms of text at consecutive
+// positions. A single word is a phrase of one term.
+type phraseQuery string
+
+// andQuery matches documents matching all its nodes, NOT nodes exclude
+// documents.
+type andQuery []queryNode
+
+// orQuery matches documents matching any of its nodes.
+type orQuery []queryNode
+
+// notQuery matches documents not matching its node.
+type notQuery struct{ node queryNode }
+
+func (q phraseQuery) match(ix *Index) (map[string]float64, bool) {
+	tokens := ix.analyzer.Analyze(string(q))
+	if len(tokens) == 0 {
+		return nil, false
+	}
+
+	first := tokens[0]
+	matches := make(map[string]float64)
+	for id, positions := range ix.postings[first.Stem] {
+		count := 0 // Occurrences of the phrase
+		for _, pos := range positions {
+			if hasPhrase(ix, id, tokens, pos-first.Position) {
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+
+		score := 0.0
+		for _, tok := range tokens {
+			score += ix.bm25(tok.Stem, id, count)
+		}
+		matches[id] = score
+	}
+	return matches, true
+}
+
+// hasPhrase reports whether document id has the tokens at their positions
+// shifted by offset. Stop words removed from the phrase leave gaps in the
+// positions, which must be there in the document too.
+func hasPhrase(ix *Index, id string, tokens []Token, offset int) bool {
+	for _, tok := range tokens {
+		positions := ix.postings[tok.Stem][id]
+		pos := tok.Position + offset
+		i := sort.SearchInts(positions, pos)
+		if i == len(positions) || positions[i] != pos {
+			return false
+		}
+	}
+	return true
+}
+
+func (q andQuery) match(ix *Index) (map[string]float64, bool) {
+	var (
+		matches map[string]float64
+		exclude []map[string]float64
+	)
+	for _, node := range q {
+		if not, ok := node.(notQuery); ok {
+			if m, ok := not.node.match(ix); ok {
+				exclude = append(exclude, m)
+			}
+			continue
+		}
+
+		m, ok := node.match(ix)
+		if !ok {
+			continue
+		}
+		if matches == nil {
+			matches = m
+			continue
+		}
+		for id, score := range matches {
+			if s, ok := m[id]; ok {
+				matches[id] = score + s
+			} else {
+				delete(matches, id)
+			}
+		}
+	}
+
+	if matches == nil {
+		if len(exclude) == 0 {
+			return nil, false
+		}
+		matches = ix.all()
+	}
+	for _, m := range exclude {
+		for id := range m {
+			delete(matches, id)
+		}
+	}
+	return matches, true
+}
+
+func (q orQuery) match(ix *Index) (map[string]float64, bool) {
+	var matches map[string]float64
+	for _, node := range q {
+		m, ok := node.match(ix)
+		if !ok {
+			continue
+		}
+		if matches == nil {
+			matches = make(map[string]float64)
+		}
+		for id, score := range m {
+			matches[id] += score
+		}
+	}
+	return matches, matches != nil
+}
+
+func (q notQuery) match(ix *Index) (map[string]float64, bool) {
+	m, ok := q.node.match(ix)
+	if !ok {
+		return nil, false
+	}
+
+	matches := ix.all()
+	for id := range m {
+		delete(matches, id)
+	}
+	return matches, true
+}
+
+// all returns all documents, with a score of 0.
+func (ix *Index) all() map[string]float64 {
+	matches := make(map[string]float64, len(ix.docs))
+	for id := range ix.docs {
+		matches[id] = 0
+	}
+	return matches
+}
+
+// queryToken is a lexical token of a query: '(', ')', '-', '"' (a phrase) or
+// 'w' (a word).
+type queryToken struct {
+	kind byte
+	text string
+}
+
+var errEmptyQuery = errors.New("empty query")
+
+// lexQuery splits query into tokens.
+func lexQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	for i := 0; i < len(query); {
+		r, size := utf8.DecodeRuneInString(query[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(' || r == ')':
+			tokens = append(tokens, queryToken{kind: byte(r)})
+			i++
+		case r == '"':
+			end := strings.IndexByte(query[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated phrase at %d", i)
+			}
+			tokens = append(tokens, queryToken{kind: '"', text: query[i+1 : i+1+end]})
+			i += end + 2
+		case r == '-' && i+1 < len(query) && !isQuerySpace(query[i+1:]):
+			tokens = append(tokens, queryToken{kind: '-'})
+			i++
+		default:
+			end := strings.IndexFunc(query[i:], func(r rune) bool {
+				return unicode.IsSpace(r) || strings.ContainsRune(`()"`, r)
+			})
+			if end < 0 {
+				end = len(query) - i
+			}
+			tokens = append(tokens, queryToken{kind: 'w', text: query[i : i+end]})
+			i += end
+		}
+	}
+	return tokens, nil
+}
+
+func isQuerySpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+type queryParser struct {
+	tokens []queryToken
+	i      int
+}
+
+// parseQuery parses a search query, see Index.Search for the syntax.
+func parseQuery(query string) (queryNode, error) {
+	tokens, err := lexQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errEmptyQuery
+	}
+
+	p := queryParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		return nil, fmt.Errorf("unexpected %s", tok)
+	}
+	return node, nil
+}
+
+func (p *queryParser) peek() (queryToken, bool) {
+	if p.i == len(p.tokens) {
+		return queryToken{}, false
+	}
+	return p.tokens[p.i], true
+}
+
+// isKeyword reports whether the next token is the operator op.
+func (p *queryParser) isKeyword(op string) bool {
+	tok, ok := p.peek()
+	return ok && tok.kind == 'w' && tok.text == op
+}
+
+func (p *queryParser) parseOr() (queryNode, error) {
+	node, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	or := orQuery{node}
+	for p.isKeyword("OR") {
+		p.i++
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, node)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return or, nil
+}
+
+func (p *queryParser) parseAnd() (queryNode, error) {
+	node, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	and := andQuery{node}
+	for {
+		if p.isKeyword("AND") {
+			p.i++
+		} else if tok, ok := p.peek(); !ok || tok.kind == ')' || p.isKeyword("OR") {
+			break
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, node)
+	}
+	if len(and) == 1 {
+		return and[0], nil
+	}
+	return and, nil
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	if tok, ok := p.peek(); ok && (tok.kind == '-' || p.isKeyword("NOT")) {
+		p.i++
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notQuery{node}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	tok, ok := p.peek()
+	if !ok {
+		return nil, errors.New("unexpected end of query")
+	}
+	p.i++
+
+	switch tok.kind {
+	case '"':
+		return phraseQuery(tok.text), nil
+	case '(':
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok, ok := p.peek(); !ok || tok.kind != ')' {
+			return nil, errors.New("missing )")
+		}
+		p.i++
+		return node, nil
+	case 'w':
+		if tok.text != "AND" && tok.text != "OR" {
+			return phraseQuery(tok.text), nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s", tok)
+}
+
+func (t queryToken) String() string {
+	switch t.kind {
+	case 'w':
+		return fmt.Sprintf("%q", t.text)
+	case '"':
+		return fmt.Sprintf("%q", `"`+t.text+`"`)
+	}
+	return fmt.Sprintf("%q", string(t.kind))
+}
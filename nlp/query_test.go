@@ -0,0 +1,55 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	testCases := []struct {
+		query string
+		node  queryNode
+	}{
+		{"holmes", phraseQuery("holmes")},
+		{"holmes watson", andQuery{phraseQuery("holmes"), phraseQuery("watson")}},
+		{"holmes AND watson", andQuery{phraseQuery("holmes"), phraseQuery("watson")}},
+		{`"baker street" london`, andQuery{phraseQuery("baker street"), phraseQuery("london")}},
+		{"a OR b c", orQuery{phraseQuery("a"), andQuery{phraseQuery("b"), phraseQuery("c")}}},
+		{"(a OR b) c", andQuery{orQuery{phraseQuery("a"), phraseQuery("b")}, phraseQuery("c")}},
+		{"a NOT b", andQuery{phraseQuery("a"), notQuery{phraseQuery("b")}}},
+		{"a -b", andQuery{phraseQuery("a"), notQuery{phraseQuery("b")}}},
+		{"-(a b)", notQuery{andQuery{phraseQuery("a"), phraseQuery("b")}}},
+		{"well-known - x", andQuery{phraseQuery("well-known"), phraseQuery("-"), phraseQuery("x")}},
+		{"or and not", andQuery{phraseQuery("or"), phraseQuery("and"), phraseQuery("not")}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			node, err := parseQuery(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.node, node)
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, query := range []string{
+		"", "  ", `"baker street`, "(a OR b", "a)", "()", "a AND", "OR a", "a OR", "NOT", "a AND OR b",
+	} {
+		_, err := parseQuery(query)
+		require.Error(t, err, query)
+	}
+}
+
+func FuzzParseQuery(f *testing.F) {
+	f.Add(`(a OR "b c") NOT -d`)
+	ix := newTestIndex()
+	f.Fuzz(func(t *testing.T, query string) {
+		if _, err := parseQuery(query); err != nil {
+			return
+		}
+		_, err := ix.Search(query, 0)
+		require.NoError(t, err)
+	})
+}
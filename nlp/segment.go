@@ -0,0 +1,221 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Word segmentation following the word boundary rules of Unicode Standard
+// Annex #29, see https://unicode.org/reports/tr29/#Word_Boundaries
+// The Word_Break property is approximated from the unicode package tables.
+//
+// Tailorings:
+//   - The colon is not MidLetter, so "key:value" is two words.
+//   - Letters of scripts without spaces between words, other than Han,
+//     Hiragana and Katakana (e.g. Thai) are ALetter, so a run of them is
+//     one word since we have no dictionary to split it.
+
+type wordBreak uint8
+
+const (
+	wbOther wordBreak = iota
+	wbCR
+	wbLF
+	wbNewline
+	wbExtend
+	wbZWJ
+	wbRegionalIndicator
+	wbFormat
+	wbKatakana
+	wbHebrewLetter
+	wbALetter
+	wbSingleQuote
+	wbDoubleQuote
+	wbMidNumLet
+	wbMidLetter
+	wbMidNum
+	wbNumeric
+	wbExtendNumLet
+	wbWSegSpace
+	wbPictographic
+)
+
+// wordBreakOf returns the Word_Break property of r.
+func wordBreakOf(r rune) wordBreak {
+	switch r {
+	case '\r':
+		return wbCR
+	case '\n':
+		return wbLF
+	case 0x0B, 0x0C, 0x85, 0x2028, 0x2029:
+		return wbNewline
+	case 0x200D:
+		return wbZWJ
+	case 0x200C:
+		return wbExtend
+	case 0x200B, 0xA0, 0x2007:
+		return wbOther
+	case '\'':
+		return wbSingleQuote
+	case '"':
+		return wbDoubleQuote
+	case '.', 0x2018, 0x2019, 0x2024, 0xFE52, 0xFF07, 0xFF0E:
+		return wbMidNumLet
+	case 0xB7, 0x0387, 0x055F, 0x05F4, 0x2027, 0xFE13, 0xFE55, 0xFF1A:
+		return wbMidLetter
+	case ',', ';', 0x037E, 0x0589, 0x060C, 0x060D, 0x066C, 0x07F8, 0x2044,
+		0xFE10, 0xFE14, 0xFE50, 0xFE54, 0xFF0C, 0xFF1B:
+		return wbMidNum
+	case 0x202F:
+		return wbExtendNumLet
+	case 0x3031, 0x3032, 0x3033, 0x3034, 0x3035, 0x309B, 0x309C, 0x30A0, 0x30FC, 0xFF70:
+		return wbKatakana
+	}
+
+	switch {
+	case 0x1F1E6 <= r && r <= 0x1F1FF:
+		return wbRegionalIndicator
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return wbExtend
+	case unicode.Is(unicode.Cf, r):
+		return wbFormat
+	case unicode.Is(unicode.Katakana, r):
+		return wbKatakana
+	case unicode.Is(unicode.Hebrew, r) && unicode.IsLetter(r):
+		return wbHebrewLetter
+	case unicode.In(r, unicode.Han, unicode.Hiragana):
+		return wbOther // One word per ideograph
+	case unicode.IsLetter(r), unicode.Is(unicode.Nl, r):
+		return wbALetter
+	case unicode.Is(unicode.Nd, r):
+		return wbNumeric
+	case unicode.Is(unicode.Pc, r):
+		return wbExtendNumLet
+	case unicode.Is(unicode.Zs, r):
+		return wbWSegSpace
+	case unicode.Is(unicode.So, r):
+		return wbPictographic
+	}
+	return wbOther
+}
+
+func isNewline(wb wordBreak) bool {
+	return wb == wbCR || wb == wbLF || wb == wbNewline
+}
+
+// isIgnored reports whether wb is skipped by rule WB4.
+func isIgnored(wb wordBreak) bool {
+	return wb == wbExtend || wb == wbFormat || wb == wbZWJ
+}
+
+func isAHLetter(wb wordBreak) bool {
+	return wb == wbALetter || wb == wbHebrewLetter
+}
+
+func isMidNumLetQ(wb wordBreak) bool {
+	return wb == wbMidNumLet || wb == wbSingleQuote
+}
+
+// peekWordBreak returns the Word_Break property of the first rune at or
+// after text[i:] that is not ignored by WB4, wbOther at the end of text.
+func peekWordBreak(text string, i int) wordBreak {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if wb := wordBreakOf(r); !isIgnored(wb) {
+			return wb
+		}
+		i += size
+	}
+	return wbOther
+}
+
+// joinsWord reports whether there is no word boundary before cur, by rules
+// WB5 to WB16. prevPrev and prev are the previous runes, next is the rune
+// after cur, all ignoring WB4 runes. ris is the number of regional indicators
+// just before cur.
+func joinsWord(prevPrev, prev, cur, next wordBreak, ris int) bool {
+	switch {
+	case isAHLetter(prev) && isAHLetter(cur): // WB5
+	case isAHLetter(prev) && (cur == wbMidLetter || isMidNumLetQ(cur)) && isAHLetter(next): // WB6
+	case isAHLetter(prevPrev) && (prev == wbMidLetter || isMidNumLetQ(prev)) && isAHLetter(cur): // WB7
+	case prev == wbHebrewLetter && cur == wbSingleQuote: // WB7a
+	case prev == wbHebrewLetter && cur == wbDoubleQuote && next == wbHebrewLetter: // WB7b
+	case prevPrev == wbHebrewLetter && prev == wbDoubleQuote && cur == wbHebrewLetter: // WB7c
+	case prev == wbNumeric && cur == wbNumeric: // WB8
+	case isAHLetter(prev) && cur == wbNumeric: // WB9
+	case prev == wbNumeric && isAHLetter(cur): // WB10
+	case prevPrev == wbNumeric && (prev == wbMidNum || isMidNumLetQ(prev)) && cur == wbNumeric: // WB11
+	case prev == wbNumeric && (cur == wbMidNum || isMidNumLetQ(cur)) && next == wbNumeric: // WB12
+	case prev == wbKatakana && cur == wbKatakana: // WB13
+	case (isAHLetter(prev) || prev == wbNumeric || prev == wbKatakana || prev == wbExtendNumLet) && cur == wbExtendNumLet: // WB13a
+	case prev == wbExtendNumLet && (isAHLetter(cur) || cur == wbNumeric || cur == wbKatakana): // WB13b
+	case prev == wbRegionalIndicator && cur == wbRegionalIndicator && ris%2 == 1: // WB15, WB16
+	default:
+		return false // WB999
+	}
+	return true
+}
+
+// nextWordBreak returns the first word boundary in text after start.
+func nextWordBreak(text string, start int) int {
+	r, size := utf8.DecodeRuneInString(text[start:])
+	last := wordBreakOf(r) // Rune just before i
+	prevPrev, prev := wbOther, last
+	ris := 0
+	if last == wbRegionalIndicator {
+		ris = 1
+	}
+
+	for i := start + size; i < len(text); i += size {
+		r, size = utf8.DecodeRuneInString(text[i:])
+		cur := wordBreakOf(r)
+
+		switch {
+		case last == wbCR && cur == wbLF: // WB3
+		case isNewline(last) || isNewline(cur): // WB3a, WB3b
+			return i
+		case last == wbZWJ && cur == wbPictographic: // WB3c
+		case last == wbWSegSpace && cur == wbWSegSpace: // WB3d
+		case isIgnored(cur): // WB4
+			last = cur
+			continue
+		case !joinsWord(prevPrev, prev, cur, peekWordBreak(text, i+size), ris):
+			return i
+		}
+
+		last = cur
+		prevPrev, prev = prev, cur
+		if cur == wbRegionalIndicator {
+			ris++
+		} else {
+			ris = 0
+		}
+	}
+	return len(text)
+}
+
+// isWordRune reports whether r makes a segment a word.
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r)
+}
+
+// span is a word at text[start:end].
+type span struct {
+	start, end int
+}
+
+// wordSpans splits text on word boundaries and returns the segments that
+// are words, dropping spaces and punctuation. Contractions and possessives
+// ("who's") and numbers ("3.14") are kept together.
+func wordSpans(text string) []span {
+	var spans []span
+	for start := 0; start < len(text); {
+		end := nextWordBreak(text, start)
+		if strings.IndexFunc(text[start:end], isWordRune) >= 0 {
+			spans = append(spans, span{start, end})
+		}
+		start = end
+	}
+	return spans
+}
@@ -0,0 +1,67 @@
+package nlp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var wordsCases = []struct {
+	text  string
+	words []string
+}{
+	{"café naïve", []string{"café", "naïve"}},
+	{"naïve", []string{"naïve"}}, // Combining diaeresis
+	{"Who's on first?", []string{"Who's", "on", "first"}},
+	{"rock’n’roll dogs' bone", []string{"rock’n’roll", "dogs", "bone"}},
+	{"$3.14, 1,000 and 42nd", []string{"3.14", "1,000", "and", "42nd"}},
+	{"U.S.A. key:value", []string{"U.S.A", "key", "value"}},
+	{"foo_bar", []string{"foo_bar"}},
+	{"Привет, мир!", []string{"Привет", "мир"}},
+	{"東京タワーに行った", []string{"東", "京", "タワー", "に", "行", "っ", "た"}},
+	{"צה\"ל", []string{"צה\"ל"}},
+	{"line1\r\nline2", []string{"line1", "line2"}},
+	{"🇺🇸🇫🇷 ... \u200b", nil},
+	{"", nil},
+}
+
+// words returns the words in text.
+func words(text string) []string {
+	var words []string
+	for _, s := range wordSpans(text) {
+		words = append(words, text[s.start:s.end])
+	}
+	return words
+}
+
+func TestWords(t *testing.T) {
+	for _, tc := range wordsCases {
+		t.Run(tc.text, func(t *testing.T) {
+			require.Equal(t, tc.words, words(tc.text))
+		})
+	}
+}
+
+func TestFold(t *testing.T) {
+	require.Equal(t, "strasse", Fold("Straße"))
+	require.Equal(t, Fold("STRASSE"), Fold("straße"))
+	require.Equal(t, "σίσυφοσ", Fold("ΣΊΣΥΦΟΣ"))
+	require.Equal(t, Fold("σίσυφος"), Fold("ΣΊΣΥΦΟΣ"))
+	require.Equal(t, "finance", Fold("ﬁnance"))
+	require.Equal(t, "\u1F00\u03B9", Fold("\u1F88")) // ᾈ
+	require.Equal(t, "ı", Fold("ı"))
+}
+
+func FuzzWords(f *testing.F) {
+	for _, tc := range wordsCases {
+		f.Add(tc.text)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		for _, w := range words(text) {
+			if !strings.Contains(text, w) {
+				t.Fatal(w)
+			}
+		}
+	})
+}
@@ -0,0 +1,193 @@
+package nlp
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Sentence is a sentence of a text.
+type Sentence struct {
+	Text string `json:"text"`
+
+	// Offsets of Text in the source, source[Start:End] == Text
+	Start     int `json:"start"`
+	End       int `json:"end"`
+	RuneStart int `json:"rune_start"`
+	RuneEnd   int `json:"rune_end"`
+}
+
+// abbreviations are words ending with a period that do not end a sentence.
+var abbreviations = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "dr": true, "prof": true, "sr": true,
+	"jr": true, "st": true, "mt": true, "rev": true, "gen": true, "col": true,
+	"capt": true, "lt": true, "sgt": true, "hon": true, "messrs": true,
+	"vs": true, "etc": true, "cf": true, "al": true, "approx": true,
+	"inc": true, "ltd": true, "co": true, "corp": true, "dept": true,
+	"vol": true, "fig": true, "p": true, "pp": true,
+	"jan": true, "feb": true, "mar": true, "apr": true, "jun": true, "jul": true,
+	"aug": true, "sep": true, "sept": true, "oct": true, "nov": true, "dec": true,
+}
+
+// numberAbbreviations are abbreviations only before a number: "No. 5", but
+// "The answer is no."
+var numberAbbreviations = map[string]bool{"no": true, "nos": true}
+
+func isTerminator(r rune) bool {
+	return strings.ContainsRune(".!?…。！？", r)
+}
+
+// isCloser reports whether r is a closing quote or bracket, which belongs
+// to the sentence before it.
+func isCloser(r rune) bool {
+	return strings.ContainsRune("\"')]}’”»", r)
+}
+
+func isOpener(r rune) bool {
+	return strings.ContainsRune("\"'([{‘“«", r)
+}
+
+// Sentences splits text into sentences. A sentence ends with a run of
+// terminators (".", "!", "?", "...", "…" and CJK full stops), with the
+// quotes or brackets closing it, and followed by a space. It does not end at:
+//   - Abbreviations ("Mr.", "e.g.") and initials ("J. Watson").
+//   - Decimals ("3.14") and other periods not followed by a space.
+//   - Any terminator followed by a lower case word ("Wait... what?").
+//
+// A blank line always ends a sentence, but single newlines do not, since
+// text is often wrapped. Use SentencesByLine for text that isn't.
+func Sentences(text string) []Sentence {
+	return splitSentences(text, false)
+}
+
+// SentencesByLine splits text into sentences like Sentences, but every
+// newline ends a sentence. It suits text with a sentence, title or list
+// item per line.
+func SentencesByLine(text string) []Sentence {
+	return splitSentences(text, true)
+}
+
+// splitSentences splits text into sentences, ending one at every newline if
+// lines is true and only at blank lines otherwise.
+func splitSentences(text string, lines bool) []Sentence {
+	var sentences []Sentence
+	start, runes := 0, 0 // runes is the rune count of text[:start]
+	emit := func(end int) {
+		s := text[start:end]
+		lead := len(s) - len(strings.TrimLeftFunc(s, unicode.IsSpace))
+		s = strings.TrimSpace(s)
+		if s != "" {
+			runeStart := runes + utf8.RuneCountInString(text[start:start+lead])
+			sentences = append(sentences, Sentence{
+				Text:      s,
+				Start:     start + lead,
+				End:       start + lead + len(s),
+				RuneStart: runeStart,
+				RuneEnd:   runeStart + utf8.RuneCountInString(s),
+			})
+		}
+		runes += utf8.RuneCountInString(text[start:end])
+		start = end
+	}
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\n' && lines:
+			emit(i)
+		case r == '\n':
+			j := i + size
+			for j < len(text) && strings.IndexByte(" \t\r", text[j]) >= 0 {
+				j++
+			}
+			if j < len(text) && text[j] == '\n' { // Blank line
+				emit(i)
+				i = j
+				continue
+			}
+		case isTerminator(r):
+			end := i + size
+			for end < len(text) {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if !isTerminator(r) {
+					break
+				}
+				end += size
+			}
+			period := end == i+1 && r == '.' // A single period
+			for end < len(text) {
+				r, size := utf8.DecodeRuneInString(text[end:])
+				if !isCloser(r) {
+					break
+				}
+				end += size
+			}
+			if endsSentence(text, i, end, period) {
+				emit(end)
+			}
+			i = end
+			continue
+		}
+		i += size
+	}
+	emit(len(text))
+
+	return sentences
+}
+
+// endsSentence reports whether the terminators at text[i:end], with their
+// closing quotes, end a sentence. period is true for a single period.
+func endsSentence(text string, i, end int, period bool) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	if strings.ContainsRune("。！？", r) {
+		return true // CJK text has no spaces between sentences
+	}
+
+	// Next word
+	j := end
+	if j == len(text) {
+		return true
+	}
+	if r, _ := utf8.DecodeRuneInString(text[j:]); !unicode.IsSpace(r) {
+		return false
+	}
+	var next rune
+	for j < len(text) {
+		r, n := utf8.DecodeRuneInString(text[j:])
+		if !unicode.IsSpace(r) && !isOpener(r) {
+			if unicode.IsLower(r) {
+				return false
+			}
+			next = r
+			break
+		}
+		j += n
+	}
+
+	// Abbreviations and initials take a single period
+	return !period || !isAbbreviation(lastWord(text[:i]), next)
+}
+
+// lastWord returns the word at the end of s, without opening quotes.
+func lastWord(s string) string {
+	if i := strings.LastIndexFunc(s, unicode.IsSpace); i >= 0 {
+		_, n := utf8.DecodeRuneInString(s[i:])
+		s = s[i+n:]
+	}
+	return strings.TrimLeftFunc(s, isOpener)
+}
+
+// isAbbreviation reports whether word, followed by a period and the word
+// starting with next, is an abbreviation: a known one, a single letter other
+// than "I" or one with periods ("e.g").
+func isAbbreviation(word string, next rune) bool {
+	if utf8.RuneCountInString(word) == 1 {
+		r, _ := utf8.DecodeRuneInString(word)
+		return unicode.IsLetter(r) && r != 'I'
+	}
+	lower := strings.ToLower(word)
+	if numberAbbreviations[lower] {
+		return unicode.IsDigit(next)
+	}
+	return strings.Contains(word, ".") || abbreviations[lower]
+}
@@ -0,0 +1,63 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var sentencesCases = []struct {
+	text      string
+	sentences []string
+}{
+	{"Who's on first? What's on second! I don't know.", []string{"Who's on first?", "What's on second!", "I don't know."}},
+	{"Mr. Holmes met Dr. Watson at 221B Baker St. in London.", []string{"Mr. Holmes met Dr. Watson at 221B Baker St. in London."}},
+	{"Some fruits, e.g. apples, are red. Others are not.", []string{"Some fruits, e.g. apples, are red.", "Others are not."}},
+	{"J. Watson paid 3.50 pounds. Holmes paid 4.", []string{"J. Watson paid 3.50 pounds.", "Holmes paid 4."}},
+	{"So did I. Then he left.", []string{"So did I.", "Then he left."}},
+	{"The answer is no. We left early.", []string{"The answer is no.", "We left early."}},
+	{"See No. 5 and Nos. 6 and 7.", []string{"See No. 5 and Nos. 6 and 7."}},
+	{"Wait... what? Well... It is late.", []string{"Wait... what?", "Well...", "It is late."}},
+	{"Wait… It is late.", []string{"Wait…", "It is late."}},
+	{`"Is it you?" he asked. "Yes!" she said.`, []string{`"Is it you?" he asked.`, `"Yes!" she said.`}},
+	{"(It was late.) We left.", []string{"(It was late.)", "We left."}},
+	{"A title\n\nThe first line\nwraps here. Done", []string{"A title", "The first line\nwraps here.", "Done"}},
+	{"Visit example.com today.", []string{"Visit example.com today."}},
+	{"今日は晴れ。明日は雨！", []string{"今日は晴れ。", "明日は雨！"}},
+	{"  ", nil},
+	{"", nil},
+}
+
+func TestSentences(t *testing.T) {
+	for _, tc := range sentencesCases {
+		t.Run(tc.text, func(t *testing.T) {
+			var texts []string
+			for _, s := range Sentences(tc.text) {
+				texts = append(texts, s.Text)
+			}
+			require.Equal(t, tc.sentences, texts)
+		})
+	}
+}
+
+func TestSentencesByLine(t *testing.T) {
+	text := "A title\r\nThe first line\nends here. Done\n\n- Mr. Holmes\n"
+	var texts []string
+	for _, s := range SentencesByLine(text) {
+		texts = append(texts, s.Text)
+		require.Equal(t, s.Text, text[s.Start:s.End])
+	}
+	require.Equal(t, []string{"A title", "The first line", "ends here.", "Done", "- Mr. Holmes"}, texts)
+}
+
+func TestSentencesOffsets(t *testing.T) {
+	text := "  Café is open.  Élan arrives. "
+	runes := []rune(text)
+	sentences := Sentences(text)
+	require.Len(t, sentences, 2)
+	require.Equal(t, Sentence{Text: "Café is open.", Start: 2, End: 16, RuneStart: 2, RuneEnd: 15}, sentences[0])
+	for _, s := range sentences {
+		require.Equal(t, s.Text, text[s.Start:s.End])
+		require.Equal(t, s.Text, string(runes[s.RuneStart:s.RuneEnd]))
+	}
+}
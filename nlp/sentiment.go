@@ -0,0 +1,323 @@
+package nlp
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Sentiment rules parameters, empirically derived in the VADER paper (Hutto
+// & Gilbert, 2014)
+const (
+	boosterIncrement    = 0.293 // Valence added by an intensifier: "very good"
+	capsIncrement       = 0.733 // Valence added by an upper case word: "GOOD"
+	negationScalar      = -0.74 // Valence multiplier of a negated word: "not good"
+	exclaimIncrement    = 0.292 // Valence added by each '!', up to maxExclaims
+	maxExclaims         = 4
+	questionIncrement   = 0.18 // Valence added by each '?' if there are 2 or 3
+	maxQuestionEmphasis = 0.96
+	butBefore           = 0.5 // Valence multiplier of the words before "but"
+	butAfter            = 1.5 // Valence multiplier of the words after "but"
+	compoundAlpha       = 15  // Normalizes the valence sum to about -1..1
+)
+
+// distanceDamping weakens intensifiers 1, 2 and 3 words before a word.
+var distanceDamping = [3]float64{1, 0.95, 0.9}
+
+// boosters are the words intensifying (positive) or dampening (negative) the
+// valence of the words after them.
+var boosters = map[string]float64{
+	"absolutely": boosterIncrement, "amazingly": boosterIncrement, "awfully": boosterIncrement,
+	"completely": boosterIncrement, "considerably": boosterIncrement, "decidedly": boosterIncrement,
+	"deeply": boosterIncrement, "enormously": boosterIncrement, "entirely": boosterIncrement,
+	"especially": boosterIncrement, "exceptionally": boosterIncrement, "extremely": boosterIncrement,
+	"fully": boosterIncrement, "greatly": boosterIncrement, "highly": boosterIncrement,
+	"hugely": boosterIncrement, "incredibly": boosterIncrement, "intensely": boosterIncrement,
+	"more": boosterIncrement, "most": boosterIncrement, "particularly": boosterIncrement,
+	"purely": boosterIncrement, "quite": boosterIncrement, "really": boosterIncrement,
+	"remarkably": boosterIncrement, "so": boosterIncrement, "substantially": boosterIncrement,
+	"such": boosterIncrement, "thoroughly": boosterIncrement, "too": boosterIncrement,
+	"totally": boosterIncrement, "tremendously": boosterIncrement, "truly": boosterIncrement,
+	"unbelievably": boosterIncrement, "unusually": boosterIncrement, "utterly": boosterIncrement,
+	"very": boosterIncrement,
+
+	"almost": -boosterIncrement, "barely": -boosterIncrement, "hardly": -boosterIncrement,
+	"kinda": -boosterIncrement, "less": -boosterIncrement, "little": -boosterIncrement,
+	"marginally": -boosterIncrement, "occasionally": -boosterIncrement, "partly": -boosterIncrement,
+	"scarcely": -boosterIncrement, "slightly": -boosterIncrement, "somewhat": -boosterIncrement,
+	"sorta": -boosterIncrement,
+}
+
+// negations are the words negating the words after them, with the words
+// ending in "n't".
+var negations = map[string]bool{
+	"aint": true, "arent": true, "cannot": true, "cant": true, "couldnt": true, "didnt": true,
+	"doesnt": true, "dont": true, "hadnt": true, "hasnt": true, "havent": true, "isnt": true,
+	"neither": true, "never": true, "no": true, "none": true, "nope": true, "nor": true,
+	"not": true, "nothing": true, "nowhere": true, "rarely": true, "seldom": true,
+	"shouldnt": true, "wasnt": true, "werent": true, "without": true, "wont": true,
+	"wouldnt": true,
+}
+
+// Sentiment are the polarity scores of a text.
+type Sentiment struct {
+	Positive float64 `json:"pos"`      // Proportion of the text that is positive
+	Negative float64 `json:"neg"`      // Proportion of the text that is negative
+	Neutral  float64 `json:"neu"`      // Proportion of the text that is neutral
+	Compound float64 `json:"compound"` // Overall polarity, from -1 (most negative) to 1 (most positive)
+}
+
+// SentimentAnalyzer scores the sentiment of texts with a lexicon of word
+// valences, adjusted by rules as in VADER:
+//   - negations flip and dampen the valence of the next 3 words: "not good"
+//   - intensifiers ("very") and dampeners ("slightly") shift it
+//   - an upper case word in mixed case text is emphasized: "GOOD"
+//   - exclamation marks, and several question marks, emphasize the text
+//   - words after "but" weigh more than the words before
+//
+// Words are looked up case folded, then by their Lemma.
+type SentimentAnalyzer struct {
+	lexicon map[string]float64 // Word -> valence
+}
+
+// NewSentimentAnalyzer returns an analyzer using lexicon, mapping words to
+// their valence from -4 (most negative) to 4 (most positive).
+func NewSentimentAnalyzer(lexicon map[string]float64) *SentimentAnalyzer {
+	sa := &SentimentAnalyzer{lexicon: make(map[string]float64, len(lexicon))}
+	for word, valence := range lexicon {
+		sa.lexicon[normalizeWord(word)] = valence
+	}
+	return sa
+}
+
+// LoadSentimentAnalyzer reads a lexicon of "word valence" lines, empty lines
+// and lines starting with # are ignored.
+func LoadSentimentAnalyzer(r io.Reader) (*SentimentAnalyzer, error) {
+	lexicon := make(map[string]float64)
+	s := bufio.NewScanner(r)
+	for lnum := 1; s.Scan(); lnum++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, valence, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("%d: missing valence", lnum)
+		}
+		v, err := strconv.ParseFloat(valence, 64)
+		if err != nil || math.IsNaN(v) || math.Abs(v) > 4 {
+			return nil, fmt.Errorf("%d: bad valence %q", lnum, valence)
+		}
+		lexicon[word] = v
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return NewSentimentAnalyzer(lexicon), nil
+}
+
+// Polarity returns the sentiment scores of text. It works best on short
+// texts such as sentences or reviews: in a long text the compound score
+// saturates. A text without words scores 0 everywhere.
+func (sa *SentimentAnalyzer) Polarity(text string) Sentiment {
+	words := sentimentWords(text)
+	if len(words) == 0 {
+		return Sentiment{}
+	}
+
+	numCaps := 0
+	for _, w := range words {
+		if isShouting(w) {
+			numCaps++
+		}
+	}
+	capsDiff := numCaps > 0 && numCaps < len(words) // Upper case stands out
+
+	folded := make([]string, len(words))
+	for i, w := range words {
+		folded[i] = normalizeWord(w)
+	}
+
+	valences := make([]float64, len(words))
+	but := -1
+	for i, word := range folded {
+		if word == "but" && but < 0 {
+			but = i
+		}
+		if _, ok := boosters[word]; ok || isKindOf(folded, i) {
+			continue
+		}
+		v, ok := sa.valence(word)
+		if !ok {
+			continue
+		}
+		if capsDiff && isShouting(words[i]) {
+			v += math.Copysign(capsIncrement, v)
+		}
+
+		// Intensifiers and negations, weaker further away
+		for dist := 1; dist <= 3 && dist <= i; dist++ {
+			prev := folded[i-dist]
+			if _, ok := sa.valence(prev); ok {
+				continue
+			}
+			scalar, ok := boosters[prev]
+			if !ok && dist == 1 && isKindOf(folded, i-2) {
+				scalar, ok = -boosterIncrement, true // "kind of good"
+			}
+			if ok {
+				if v < 0 {
+					scalar = -scalar // Intensifiers make negative words more negative
+				}
+				if capsDiff && isShouting(words[i-dist]) {
+					scalar += math.Copysign(capsIncrement, v)
+				}
+				v += scalar * distanceDamping[dist-1]
+			}
+			if isNegation(prev) {
+				v *= negationScalar
+			}
+		}
+		valences[i] = v
+	}
+
+	if but >= 0 {
+		for i := range valences {
+			switch {
+			case i < but:
+				valences[i] *= butBefore
+			case i > but:
+				valences[i] *= butAfter
+			}
+		}
+	}
+	return sentimentScores(valences, punctuationEmphasis(text))
+}
+
+// valence returns the valence of a case folded word, from the lexicon or
+// else from its lemma.
+func (sa *SentimentAnalyzer) valence(word string) (float64, bool) {
+	if v, ok := sa.lexicon[word]; ok {
+		return v, true
+	}
+	v, ok := sa.lexicon[Lemma(word)]
+	return v, ok
+}
+
+// sentimentScores returns the sentiment of the word valences, the text is
+// emphasized by emphasis in its overall direction.
+func sentimentScores(valences []float64, emphasis float64) Sentiment {
+	sum, pos, neg, neu := 0.0, 0.0, 0.0, 0.0
+	for _, v := range valences {
+		sum += v
+		// +1 and -1 so words with a small valence count more than neutral ones
+		switch {
+		case v > 0:
+			pos += v + 1
+		case v < 0:
+			neg += v - 1
+		default:
+			neu++
+		}
+	}
+
+	switch {
+	case sum > 0:
+		sum += emphasis
+	case sum < 0:
+		sum -= emphasis
+	}
+	switch {
+	case pos > -neg:
+		pos += emphasis
+	case pos < -neg:
+		neg -= emphasis
+	}
+
+	total := pos - neg + neu
+	return Sentiment{
+		Positive: pos / total,
+		Negative: math.Abs(neg) / total, // Not -0
+		Neutral:  neu / total,
+		Compound: max(-1, min(1, sum/math.Sqrt(sum*sum+compoundAlpha))),
+	}
+}
+
+// punctuationEmphasis returns the valence added by the exclamation and
+// question marks of text.
+func punctuationEmphasis(text string) float64 {
+	emphasis := float64(min(strings.Count(text, "!"), maxExclaims)) * exclaimIncrement
+	switch n := strings.Count(text, "?"); {
+	case n > 3:
+		emphasis += maxQuestionEmphasis
+	case n > 1:
+		emphasis += float64(n) * questionIncrement
+	}
+	return emphasis
+}
+
+// sentimentWords splits text at spaces and trims the punctuation around the
+// words, unless that leaves at most one rune so emoticons like ":)" and ":D"
+// are kept.
+func sentimentWords(text string) []string {
+	fields := strings.Fields(text)
+	words := fields[:0]
+	for _, f := range fields {
+		w := strings.TrimFunc(f, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if len([]rune(w)) <= 1 {
+			w = f
+		}
+		words = append(words, w)
+	}
+	return words
+}
+
+// isShouting reports whether word has letters, all upper case.
+func isShouting(word string) bool {
+	letters := false
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+		letters = letters || unicode.IsUpper(r)
+	}
+	return letters
+}
+
+// isNegation reports whether the case folded word negates the next words.
+func isNegation(word string) bool {
+	return negations[word] || strings.HasSuffix(word, "n't")
+}
+
+// isKindOf reports whether words[i] starts "kind of" or "sort of", which
+// dampen the next word.
+func isKindOf(words []string, i int) bool {
+	return i >= 0 && i+1 < len(words) && (words[i] == "kind" || words[i] == "sort") && words[i+1] == "of"
+}
+
+// English sentiment lexicon
+//
+//go:embed sentiment/en.txt
+var sentimentLexicon string
+
+var defaultSentimentAnalyzer = sync.OnceValue(func() *SentimentAnalyzer {
+	sa, err := LoadSentimentAnalyzer(strings.NewReader(sentimentLexicon))
+	if err != nil {
+		panic(fmt.Sprintf("nlp: bad embedded sentiment lexicon - %s", err))
+	}
+	return sa
+})
+
+// Polarity returns the sentiment scores of English text with the built-in
+// lexicon, see SentimentAnalyzer.
+func Polarity(text string) Sentiment {
+	return defaultSentimentAnalyzer().Polarity(text)
+}
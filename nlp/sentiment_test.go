@@ -0,0 +1,89 @@
+package nlp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolarity(t *testing.T) {
+	// Compound scores of the VADER reference implementation, with the same
+	// valence for "good"
+	s := Polarity("The food is good.")
+	require.InDelta(t, 0.4404, s.Compound, 1e-4)
+	require.InDelta(t, 1, s.Positive+s.Negative+s.Neutral, 1e-9)
+	require.Zero(t, s.Negative)
+
+	require.Equal(t, Sentiment{Neutral: 1}, Polarity("The book is on the table."))
+	require.Equal(t, Sentiment{}, Polarity(""))
+	require.Equal(t, Sentiment{}, Polarity("  \n"))
+}
+
+func TestPolarityRules(t *testing.T) {
+	compound := func(text string) float64 {
+		return Polarity(text).Compound
+	}
+	good := compound("The food is good.")
+
+	testCases := []struct {
+		name string
+		text string
+		less bool // Less positive than "The food is good."
+	}{
+		{"negation", "The food is not good.", true},
+		{"negation contraction", "The food isn't good.", true},
+		{"negation curly contraction", "The food isn’t good.", true},
+		{"intensifier", "The food is very good.", false},
+		{"dampener", "The food is slightly good.", true},
+		{"kind of", "The food is kind of good.", true},
+		{"caps", "The food is GOOD.", false},
+		{"exclamation", "The food is good!", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := compound(tc.text)
+			if tc.less {
+				require.Less(t, c, good)
+			} else {
+				require.GreaterOrEqual(t, c, good)
+			}
+		})
+	}
+
+	require.Less(t, compound("The food is not good."), 0.0)
+	require.Greater(t, compound("The food is not bad."), 0.0)
+	require.Less(t, compound("The food is very bad."), compound("The food is bad."))
+	require.Greater(t, compound("The food is good!!!"), compound("The food is good!"))
+	require.Equal(t, compound("The food is good!!!!"), compound("The food is good!!!!!!"))
+	require.Greater(t, compound("Is the food good??"), compound("Is the food good?"))
+	require.Equal(t, compound("GOOD FOOD"), compound("good food"), "all caps isn't emphasis")
+	require.Less(t, compound("I hated the service"), compound("I hated the service :)"))
+	require.Greater(t, compound("She loves the food"), 0.0, "lemma of loves")
+
+	// Words after "but" weigh more
+	require.Less(t, compound("The food was great, but the service was horrible."), 0.0)
+	require.Greater(t, compound("The service was horrible, but the food was great."), 0.0)
+
+	// Negations reach 3 words
+	require.Less(t, compound("The food is not really very good."), 0.0)
+	require.Greater(t, compound("Not that the food was ever that good."), 0.0)
+}
+
+func TestLoadSentimentAnalyzer(t *testing.T) {
+	lexicon := `
+# Test lexicon
+Yummy 3
+yuck -2.5
+`
+	sa, err := LoadSentimentAnalyzer(strings.NewReader(lexicon))
+	require.NoError(t, err)
+	require.Greater(t, sa.Polarity("It was yummy").Compound, 0.0)
+	require.Less(t, sa.Polarity("It was YUCK").Compound, 0.0)
+	require.Equal(t, Sentiment{Neutral: 1}, sa.Polarity("It was good"))
+
+	for _, data := range []string{"yummy", "yummy x", "yummy 5", "yummy NaN"} {
+		_, err := LoadSentimentAnalyzer(strings.NewReader(data))
+		require.Error(t, err, data)
+	}
+}
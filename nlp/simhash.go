@@ -0,0 +1,154 @@
+package nlp
+
+import "math/bits"
+
+// SimHash returns the 64-bit SimHash fingerprint of features (Charikar,
+// 2002): each bit is the majority vote of that bit in the hashes of the
+// features, a feature given several times votes several times. Unlike
+// ordinary hashes, similar sets of features get fingerprints differing in
+// few bits, see HammingDistance.
+func SimHash(features []string) uint64 {
+	var votes [64]int
+	for _, f := range features {
+		h := hashString(f)
+		for i := range votes {
+			if h&(1<<i) != 0 {
+				votes[i]++
+			} else {
+				votes[i]--
+			}
+		}
+	}
+
+	var fp uint64
+	for i, v := range votes {
+		if v > 0 {
+			fp |= 1 << i
+		}
+	}
+	return fp
+}
+
+// HammingDistance returns the number of bits that differ between a and b.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// SimHashIndex finds near-duplicate documents, whose SimHash fingerprints
+// of word shingles differ by at most a number of bits. The fingerprints are
+// cut into one more block than that number: two fingerprints this close
+// share at least a block, so only the documents sharing a block are
+// compared (Manku et al., 2007).
+//
+// SimHash is more compact than MinHash, but less precise on short texts.
+//
+// A SimHashIndex is not safe for concurrent use while documents are added.
+type SimHashIndex struct {
+	maxDist int
+	buckets []map[uint64][]string // Block -> bits of the block -> document IDs
+	prints  map[string]uint64     // Document ID -> fingerprint
+	ids     map[string]bool       // All documents, with texts without words
+}
+
+// NewSimHashIndex returns an empty index of documents similar when their
+// fingerprints differ by at most maxDist bits, between 0 and 63. 3 is common
+// for web pages, shorter texts such as paragraphs need about 10: their
+// fingerprints vary more for the same edits.
+func NewSimHashIndex(maxDist int) *SimHashIndex {
+	maxDist = max(0, min(maxDist, 63))
+	buckets := make([]map[uint64][]string, maxDist+1)
+	for i := range buckets {
+		buckets[i] = make(map[uint64][]string)
+	}
+	return &SimHashIndex{
+		maxDist: maxDist,
+		buckets: buckets,
+		prints:  make(map[string]uint64),
+		ids:     make(map[string]bool),
+	}
+}
+
+// Add adds document id with text and reports whether id was missing, text
+// is ignored if not. A text without words is never a duplicate.
+func (s *SimHashIndex) Add(id, text string) bool {
+	if s.ids[id] {
+		return false
+	}
+	s.ids[id] = true
+
+	shingles := documentShingles(text)
+	if len(shingles) == 0 {
+		return true
+	}
+	fp := SimHash(shingles)
+	s.prints[id] = fp
+	for block, key := range s.blockKeys(fp) {
+		s.buckets[block][key] = append(s.buckets[block][key], id)
+	}
+	return true
+}
+
+// Len returns the number of documents.
+func (s *SimHashIndex) Len() int {
+	return len(s.ids)
+}
+
+// Similar returns the documents similar to text, most similar first. The
+// similarity is the fraction of equal fingerprint bits.
+func (s *SimHashIndex) Similar(text string) []Duplicate {
+	shingles := documentShingles(text)
+	if len(shingles) == 0 {
+		return nil
+	}
+
+	fp := SimHash(shingles)
+	seen := make(map[string]bool)
+	var dups []Duplicate
+	for block, key := range s.blockKeys(fp) {
+		for _, id := range s.buckets[block][key] {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if d := HammingDistance(fp, s.prints[id]); d <= s.maxDist {
+				dups = append(dups, Duplicate{ID: id, Similarity: 1 - float64(d)/64})
+			}
+		}
+	}
+	sortDuplicates(dups)
+	return dups
+}
+
+// Clusters returns the groups of similar documents, see
+// MinHashIndex.Clusters.
+func (s *SimHashIndex) Clusters() [][]string {
+	ds := newDisjointSets()
+	checked := make(map[[2]string]bool)
+	for _, block := range s.buckets {
+		for _, ids := range block {
+			for i, a := range ids {
+				for _, b := range ids[i+1:] {
+					pair := [2]string{a, b}
+					if checked[pair] {
+						continue
+					}
+					checked[pair] = true
+					if HammingDistance(s.prints[a], s.prints[b]) <= s.maxDist {
+						ds.union(a, b)
+					}
+				}
+			}
+		}
+	}
+	return ds.sets()
+}
+
+// blockKeys returns the bits of the blocks of fp.
+func (s *SimHashIndex) blockKeys(fp uint64) []uint64 {
+	keys := make([]uint64, len(s.buckets))
+	for block := range keys {
+		lo, hi := block*64/len(keys), (block+1)*64/len(keys)
+		keys[block] = fp >> lo & (1<<(hi-lo) - 1)
+	}
+	return keys
+}
@@ -0,0 +1,50 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimHash(t *testing.T) {
+	a := Shingles("The quick brown fox jumps over the lazy dog", 3)
+	b := Shingles("The quick brown fox jumped over the lazy dog", 3)
+	c := Shingles("Colorless green ideas sleep furiously", 3)
+
+	require.Equal(t, SimHash(a), SimHash(a))
+	require.Less(t, HammingDistance(SimHash(a), SimHash(b)), HammingDistance(SimHash(a), SimHash(c)))
+	require.Equal(t, uint64(0), SimHash(nil))
+	require.Equal(t, hashString("fox"), SimHash([]string{"fox"}))
+}
+
+func TestHammingDistance(t *testing.T) {
+	require.Equal(t, 0, HammingDistance(42, 42))
+	require.Equal(t, 2, HammingDistance(0b1010, 0b0110))
+	require.Equal(t, 64, HammingDistance(0, ^uint64(0)))
+}
+
+func TestSimHashIndex(t *testing.T) {
+	docs, clusters := nearDuplicates(t)
+	s := NewSimHashIndex(10) // Paragraphs are short
+	for id, text := range docs {
+		require.True(t, s.Add(id, text))
+	}
+	require.False(t, s.Add("p010", "ignored"))
+	require.True(t, s.Add("empty", ""))
+	require.Equal(t, len(docs)+1, s.Len())
+
+	require.Equal(t, clusters, s.Clusters())
+
+	similar := s.Similar(docs["p050-copy"])
+	require.Len(t, similar, 2)
+	require.Equal(t, Duplicate{ID: "p050-copy", Similarity: 1}, similar[0])
+	require.Equal(t, "p050", similar[1].ID)
+	require.Empty(t, s.Similar(""))
+
+	// Exact duplicates only
+	s = NewSimHashIndex(0)
+	s.Add("a", docs["p010"])
+	s.Add("b", docs["p010"])
+	s.Add("c", docs["p010-copy"])
+	require.Equal(t, [][]string{{"a", "b"}}, s.Clusters())
+}
@@ -0,0 +1,253 @@
+package nlp
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxDistance is the edit distance of suggestions of the built-in
+// spell checker, which catches most typos.
+const DefaultMaxDistance = 2
+
+// maxSuggestions is the maximal number of suggestions of Suggest.
+const maxSuggestions = 10
+
+// SpellChecker suggests corrections of misspelled words from the word
+// counts of a corpus. It uses the symmetric delete algorithm of SymSpell:
+// words are indexed by the strings left after deleting up to maxDist runes,
+// a misspelled word is then looked up by its own deletes, which is much
+// faster than generating all the edits of Norvig's corrector.
+//
+// A SpellChecker is not safe for concurrent use while words are added.
+type SpellChecker struct {
+	maxDist int
+	maxLen  int                 // Runes of the longest word
+	counts  map[string]int      // Word -> count
+	deletes map[string][]string // Delete -> words
+}
+
+// Suggestion is a possible correction of a word.
+type Suggestion struct {
+	Word     string `json:"word"`
+	Distance int    `json:"distance"` // Damerau-Levenshtein distance to the word
+	Count    int    `json:"count"`    // Occurrences in the corpus
+}
+
+// NewSpellChecker returns a spell checker without words, suggesting words up
+// to maxDist edits away.
+func NewSpellChecker(maxDist int) *SpellChecker {
+	return &SpellChecker{
+		maxDist: maxDist,
+		counts:  make(map[string]int),
+		deletes: make(map[string][]string),
+	}
+}
+
+// Add adds count occurrences of word.
+func (sc *SpellChecker) Add(word string, count int) {
+	word = normalizeWord(word)
+	if word == "" || count <= 0 {
+		return
+	}
+
+	if _, ok := sc.counts[word]; !ok {
+		for _, d := range deletes(word, sc.maxDist) {
+			sc.deletes[d] = append(sc.deletes[d], word)
+		}
+		sc.maxLen = max(sc.maxLen, utf8.RuneCountInString(word))
+	}
+	sc.counts[word] += count
+}
+
+// Train adds the words of the text read from r, words with digits or other
+// non-letters are ignored.
+func (sc *SpellChecker) Train(r io.Reader) error {
+	tok := NewTokenizer(r, WithAnalyzer(NewAnalyzer(Lowercase())))
+	for word := range tok.Terms() {
+		if isSpellWord(word) {
+			sc.Add(word, 1)
+		}
+	}
+	return tok.Err()
+}
+
+// Len returns the number of distinct words.
+func (sc *SpellChecker) Len() int {
+	return len(sc.counts)
+}
+
+// Count returns the number of occurrences of word.
+func (sc *SpellChecker) Count(word string) int {
+	return sc.counts[normalizeWord(word)]
+}
+
+// Suggest returns the known words at most maxDist edits away from word,
+// closest first, then most frequent first. A known word is its own first
+// suggestion. It returns at most n suggestions if n is positive.
+func (sc *SpellChecker) Suggest(word string, n int) []Suggestion {
+	word = normalizeWord(word)
+	if word == "" || utf8.RuneCountInString(word) > sc.maxLen+sc.maxDist {
+		return nil // Too far from any known word, don't generate its deletes
+	}
+
+	seen := make(map[string]bool)
+	var suggestions []Suggestion
+	for _, d := range deletes(word, sc.maxDist) {
+		for _, candidate := range sc.deletes[d] {
+			if seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
+			if dist := DamerauLevenshtein(word, candidate); dist <= sc.maxDist {
+				suggestions = append(suggestions, Suggestion{Word: candidate, Distance: dist, Count: sc.counts[candidate]})
+			}
+		}
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Word < b.Word
+	})
+	if n > 0 && len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+	return suggestions
+}
+
+// Correct returns the best suggestion for word, or word (normalized) if
+// there is none.
+func (sc *SpellChecker) Correct(word string) string {
+	if s := sc.Suggest(word, 1); len(s) > 0 {
+		return s[0].Word
+	}
+	return normalizeWord(word)
+}
+
+// deletes returns word and the distinct strings left after deleting up to
+// maxDist runes from it.
+func deletes(word string, maxDist int) []string {
+	seen := map[string]bool{word: true}
+	out := []string{word}
+	level := []string{word}
+	for range maxDist {
+		var next []string
+		for _, w := range level {
+			runes := []rune(w)
+			if len(runes) <= 1 {
+				continue
+			}
+			for i := range runes {
+				d := string(runes[:i]) + string(runes[i+1:])
+				if !seen[d] {
+					seen[d] = true
+					out = append(out, d)
+					next = append(next, d)
+				}
+			}
+		}
+		level = next
+	}
+	return out
+}
+
+// normalizeWord case folds word and writes its apostrophes as '.
+func normalizeWord(word string) string {
+	return strings.ReplaceAll(Fold(strings.TrimSpace(word)), "’", "'")
+}
+
+// isSpellWord reports whether word is made of letters, and apostrophes
+// inside: "don't" is a word, "1887" is not.
+func isSpellWord(word string) bool {
+	if word == "" || !unicode.IsLetter([]rune(word)[0]) {
+		return false
+	}
+	for _, r := range word {
+		if !unicode.IsLetter(r) && r != '\'' && r != '’' {
+			return false
+		}
+	}
+	return true
+}
+
+// Save writes the word counts of sc as "word count" lines, most frequent
+// first.
+func (sc *SpellChecker) Save(w io.Writer) error {
+	words := make([]string, 0, len(sc.counts))
+	for word := range sc.counts {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if sc.counts[words[i]] != sc.counts[words[j]] {
+			return sc.counts[words[i]] > sc.counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, word := range words {
+		fmt.Fprintf(bw, "%s %d\n", word, sc.counts[word])
+	}
+	return bw.Flush()
+}
+
+// LoadSpellChecker reads word counts written by Save, empty lines and lines
+// starting with # are ignored.
+func LoadSpellChecker(r io.Reader, maxDist int) (*SpellChecker, error) {
+	sc := NewSpellChecker(maxDist)
+	s := bufio.NewScanner(r)
+	for lnum := 1; s.Scan(); lnum++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		word, count, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("%d: missing count", lnum)
+		}
+		n, err := strconv.Atoi(count)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("%d: bad count %q", lnum, count)
+		}
+		sc.Add(word, n)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return sc, nil
+}
+
+// English word counts of The Adventures of Sherlock Holmes (see
+// TestSpellModel)
+//
+//go:embed spell/en.txt
+var spellModel string
+
+var defaultSpellChecker = sync.OnceValue(func() *SpellChecker {
+	sc, err := LoadSpellChecker(strings.NewReader(spellModel), DefaultMaxDistance)
+	if err != nil {
+		panic(fmt.Sprintf("nlp: bad embedded spelling model - %s", err))
+	}
+	return sc
+})
+
+// Suggest returns up to 10 corrections of an English word, best first, see
+// SpellChecker.Suggest. A correctly spelled word is its own first
+// suggestion. The built-in vocabulary is small (about 8,000 words), so rare
+// words may be "corrected".
+func Suggest(word string) []Suggestion {
+	return defaultSpellChecker().Suggest(word, maxSuggestions)
+}
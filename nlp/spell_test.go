@@ -0,0 +1,105 @@
+package nlp
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateSpelling = flag.Bool("update-spelling", false, "regenerate spell/en.txt from ../freq/sherlock.txt")
+
+// TestSpellModel checks that the built-in word counts are the ones of
+// ../freq/sherlock.txt, run with -update-spelling to regenerate them.
+func TestSpellModel(t *testing.T) {
+	file, err := os.Open("../freq/sherlock.txt")
+	require.NoError(t, err)
+	defer file.Close()
+
+	sc := NewSpellChecker(DefaultMaxDistance)
+	require.NoError(t, sc.Train(file))
+	var buf bytes.Buffer
+	require.NoError(t, sc.Save(&buf))
+
+	if *updateSpelling {
+		require.NoError(t, os.WriteFile("spell/en.txt", buf.Bytes(), 0o644))
+		return
+	}
+	require.Equal(t, spellModel, buf.String(), "stale model, run go test -run TestSpellModel -update-spelling")
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"the", "the"},
+		{"teh", "the"},
+		{"Holms", "holmes"},
+		{"detectve", "detective"},
+		{"sherlok", "sherlock"},
+		{"adventrue", "adventure"},
+		{"wihch", "which"},
+		{"recieve", "receive"},
+	}
+	for _, tc := range tests {
+		suggestions := Suggest(tc.word)
+		require.NotEmpty(t, suggestions, tc.word)
+		require.Equal(t, tc.want, suggestions[0].Word, "%s: %v", tc.word, suggestions)
+	}
+
+	require.Empty(t, Suggest("xqzwvk"))
+	require.Empty(t, Suggest(""))
+	require.LessOrEqual(t, len(Suggest("a")), maxSuggestions)
+}
+
+func TestSpellChecker(t *testing.T) {
+	sc := NewSpellChecker(1)
+	require.NoError(t, sc.Train(strings.NewReader("The cat sat on the mat. The bat, 42 cats!")))
+	require.Equal(t, 7, sc.Len())
+	require.Equal(t, 3, sc.Count("THE"))
+	require.Equal(t, 0, sc.Count("42"))
+
+	require.Equal(t, []Suggestion{
+		{Word: "bat", Distance: 1, Count: 1},
+		{Word: "cat", Distance: 1, Count: 1},
+		{Word: "mat", Distance: 1, Count: 1},
+		{Word: "sat", Distance: 1, Count: 1},
+	}, sc.Suggest("xat", 0))
+	require.Equal(t, []Suggestion{
+		{Word: "cat", Distance: 0, Count: 1},
+		{Word: "bat", Distance: 1, Count: 1},
+	}, sc.Suggest("cat", 2))
+	require.Equal(t, "cat", sc.Correct("cast")) // "cats" is as close and as frequent
+	require.Equal(t, "dog", sc.Correct("Dog"))
+
+	// Words longer than the known ones by more than maxDist
+	require.Equal(t, []Suggestion{{Word: "cats", Distance: 1, Count: 1}}, sc.Suggest("catsx", 0))
+	require.Nil(t, sc.Suggest("catsxy", 0))
+}
+
+func TestSuggestLongWord(t *testing.T) {
+	// Generating the deletes of such a word takes seconds and gigabytes
+	require.Nil(t, Suggest(strings.Repeat("holmes", 150)))
+}
+
+func TestSpellSaveLoad(t *testing.T) {
+	sc := NewSpellChecker(2)
+	require.NoError(t, sc.Train(strings.NewReader("It was the best of times, it was the worst of times")))
+
+	var buf bytes.Buffer
+	require.NoError(t, sc.Save(&buf))
+	require.True(t, strings.HasPrefix(buf.String(), "it 2\nof 2\n"), buf.String())
+	loaded, err := LoadSpellChecker(&buf, 2)
+	require.NoError(t, err)
+	require.Equal(t, sc.counts, loaded.counts)
+	require.Equal(t, sc.Suggest("tims", 0), loaded.Suggest("tims", 0))
+
+	for _, data := range []string{"word", "word x", "word -1"} {
+		_, err := LoadSpellChecker(strings.NewReader(data), 2)
+		require.Error(t, err, data)
+	}
+}
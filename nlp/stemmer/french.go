@@ -0,0 +1,337 @@
+package stemmer
+
+import "strings"
+
+// French Snowball stemmer.
+// See https://snowballstem.org/algorithms/french/stemmer.html
+
+var (
+	frenchVowel = vowelSet("aeiouyâàëéêèïîôûù")
+
+	frenchIVerbSuffixes = []string{
+		"îmes", "ît", "îtes", "i", "ie", "ies", "ir", "ira", "irai",
+		"iraIent", "irais", "irait", "iras", "irent", "irez", "iriez",
+		"irions", "irons", "iront", "is", "issaIent", "issais", "issait",
+		"issant", "issante", "issantes", "issants", "isse", "issent", "isses",
+		"issez", "issiez", "issions", "issons", "it",
+	}
+
+	frenchVerbSuffixes = []string{
+		"ions",
+		"é", "ée", "ées", "és", "èrent", "er", "era", "erai",
+		"eraIent", "erais", "erait", "eras", "erez", "eriez", "erions",
+		"erons", "eront", "ez", "iez",
+		"âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant",
+		"ante", "antes", "ants", "as", "asse", "assent", "asses", "assiez",
+		"assions",
+	}
+)
+
+// French returns the Snowball French stem of a lower case word.
+func French(s string) string {
+	w := &word{r: []rune(s)}
+	frenchPrelude(w)
+	frenchRegions(w)
+
+	altered := frenchStep1(w)
+	if !altered {
+		altered = frenchStep2a(w) || frenchStep2b(w)
+	}
+
+	if altered && len(w.r) > 0 {
+		// Step 3
+		switch w.r[len(w.r)-1] {
+		case 'Y':
+			w.r[len(w.r)-1] = 'i'
+		case 'ç':
+			w.r[len(w.r)-1] = 'c'
+		}
+	} else {
+		frenchStep4(w)
+	}
+
+	frenchStep5(w)
+	frenchStep6(w)
+
+	return frenchPostlude.Replace(w.String())
+}
+
+// frenchPostlude undoes frenchPrelude.
+var frenchPostlude = strings.NewReplacer("I", "i", "U", "u", "Y", "y", "He", "ë", "Hi", "ï", "H", "")
+
+// frenchPrelude marks vowels that act as consonants with upper case, and
+// writes ë and ï as He and Hi so suffixes starting with e or i match after
+// them (ambiguïté -> ambigu).
+func frenchPrelude(w *word) {
+	r := w.r
+	for i, c := range r {
+		prevVowel := i > 0 && frenchVowel(r[i-1])
+		nextVowel := i+1 < len(r) && frenchVowel(r[i+1])
+		switch {
+		case (c == 'u' || c == 'i') && prevVowel && nextVowel:
+			r[i] = c - 'a' + 'A'
+		case c == 'y' && (prevVowel || nextVowel):
+			r[i] = 'Y'
+		case c == 'u' && i > 0 && r[i-1] == 'q':
+			r[i] = 'U'
+		}
+	}
+	w.r = []rune(strings.NewReplacer("ë", "He", "ï", "Hi").Replace(string(r)))
+}
+
+func frenchRegions(w *word) {
+	r := w.r
+	w.rv = len(r)
+	switch {
+	case len(r) >= 2 && frenchVowel(r[0]) && frenchVowel(r[1]):
+		w.rv = min(3, len(r))
+	case strings.HasPrefix(string(r), "par"), strings.HasPrefix(string(r), "col"), strings.HasPrefix(string(r), "tap"):
+		w.rv = 3
+	default:
+		for i := 1; i < len(r); i++ {
+			if frenchVowel(r[i]) {
+				w.rv = i + 1
+				break
+			}
+		}
+	}
+	w.markR1R2(frenchVowel)
+}
+
+// frenchStep1 removes standard suffixes. It reports whether step 2a should
+// be skipped, which is when a suffix other than the -ment family was removed.
+func frenchStep1(w *word) bool {
+	s := w.suffix(
+		"ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes", "ismes", "ables", "istes",
+		"atrice", "ateur", "ation", "atrices", "ateurs", "ations",
+		"logie", "logies", "usion", "ution", "usions", "utions", "ence", "ences",
+		"ement", "ements", "ité", "ités", "if", "ive", "ifs", "ives",
+		"eaux", "aux", "euse", "euses", "issement", "issements",
+		"amment", "emment", "ment", "ments",
+	)
+
+	switch s {
+	case "":
+		return false
+	case "ance", "iqUe", "isme", "able", "iste", "eux", "ances", "iqUes", "ismes", "ables", "istes":
+		return w.inR2(s) && w.trim(s)
+	case "atrice", "ateur", "ation", "atrices", "ateurs", "ations":
+		if !w.inR2(s) {
+			return false
+		}
+		w.replace(s, "")
+		if w.hasSuffix("ic") {
+			if w.inR2("ic") {
+				w.replace("ic", "")
+			} else {
+				w.replace("ic", "iqU")
+			}
+		}
+		return true
+	case "logie", "logies":
+		if !w.inR2(s) {
+			return false
+		}
+		w.replace(s, "log")
+		return true
+	case "usion", "ution", "usions", "utions":
+		if !w.inR2(s) {
+			return false
+		}
+		w.replace(s, "u")
+		return true
+	case "ence", "ences":
+		if !w.inR2(s) {
+			return false
+		}
+		w.replace(s, "ent")
+		return true
+	case "ement", "ements":
+		if !w.inRV(s) {
+			return false
+		}
+		w.replace(s, "")
+		switch p := w.suffix("iv", "eus", "abl", "iqU", "ièr", "Ièr"); p {
+		case "iv":
+			if w.inR2(p) {
+				w.replace(p, "")
+				if w.hasSuffix("at") && w.inR2("at") {
+					w.replace("at", "")
+				}
+			}
+		case "eus":
+			if w.inR2(p) {
+				w.replace(p, "")
+			} else if w.inR1(p) {
+				w.replace(p, "eux")
+			}
+		case "abl", "iqU":
+			if w.inR2(p) {
+				w.replace(p, "")
+			}
+		case "ièr", "Ièr":
+			if w.inRV(p) {
+				w.replace(p, "i")
+			}
+		}
+		return true
+	case "ité", "ités":
+		if !w.inR2(s) {
+			return false
+		}
+		w.replace(s, "")
+		switch p := w.suffix("abil", "ic", "iv"); p {
+		case "abil":
+			if w.inR2(p) {
+				w.replace(p, "")
+			} else {
+				w.replace(p, "abl")
+			}
+		case "ic":
+			if w.inR2(p) {
+				w.replace(p, "")
+			} else {
+				w.replace(p, "iqU")
+			}
+		case "iv":
+			if w.inR2(p) {
+				w.replace(p, "")
+			}
+		}
+		return true
+	case "if", "ive", "ifs", "ives":
+		if !w.inR2(s) {
+			return false
+		}
+		w.replace(s, "")
+		if w.hasSuffix("at") && w.inR2("at") {
+			w.replace("at", "")
+			if w.hasSuffix("ic") {
+				if w.inR2("ic") {
+					w.replace("ic", "")
+				} else {
+					w.replace("ic", "iqU")
+				}
+			}
+		}
+		return true
+	case "eaux":
+		w.replace(s, "eau")
+		return true
+	case "aux":
+		if !w.inR1(s) {
+			return false
+		}
+		w.replace(s, "al")
+		return true
+	case "euse", "euses":
+		if w.inR2(s) {
+			w.replace(s, "")
+			return true
+		}
+		if w.inR1(s) {
+			w.replace(s, "eux")
+			return true
+		}
+		return false
+	case "issement", "issements":
+		if !w.inR1(s) || frenchVowel(w.before(s)) {
+			return false
+		}
+		w.replace(s, "")
+		return true
+	case "amment":
+		if w.inRV(s) {
+			w.replace(s, "ant")
+		}
+		return false // Go on with step 2a
+	case "emment":
+		if w.inRV(s) {
+			w.replace(s, "ent")
+		}
+		return false
+	case "ment", "ments":
+		if i := w.start(s) - 1; i >= w.rv && frenchVowel(w.r[i]) {
+			w.replace(s, "")
+		}
+		return false
+	}
+	return false
+}
+
+// frenchStep2a removes verb suffixes beginning with i.
+func frenchStep2a(w *word) bool {
+	s := w.suffixIn(w.rv, frenchIVerbSuffixes...)
+	if s == "" {
+		return false
+	}
+	if i := w.start(s) - 1; i < w.rv || frenchVowel(w.r[i]) || w.r[i] == 'H' {
+		return false // Needs a non-vowel inside RV before it, not the H of ï
+	}
+	w.replace(s, "")
+	return true
+}
+
+// frenchStep2b removes the other verb suffixes.
+func frenchStep2b(w *word) bool {
+	s := w.suffixIn(w.rv, frenchVerbSuffixes...)
+	if s == "" {
+		return false
+	}
+	switch s {
+	case "ions":
+		if !w.inR2(s) {
+			return false
+		}
+		w.replace(s, "")
+	case "âmes", "ât", "âtes", "a", "ai", "aIent", "ais", "ait", "ant",
+		"ante", "antes", "ants", "as", "asse", "assent", "asses", "assiez", "assions":
+		w.replace(s, "")
+		if w.hasSuffix("e") && w.inRV("e") {
+			w.replace("e", "")
+		}
+	default:
+		w.replace(s, "")
+	}
+	return true
+}
+
+// frenchStep4 removes residual suffixes.
+func frenchStep4(w *word) {
+	if w.hasSuffix("s") && len(w.r) > 1 && (w.hasSuffix("His") || !strings.ContainsRune("aiouès", w.before("s"))) {
+		w.replace("s", "")
+	}
+
+	s := w.suffixIn(w.rv, "ion", "ier", "ière", "Ier", "Ière", "e")
+	if s == "" {
+		return
+	}
+	switch s {
+	case "ion":
+		if b := w.start(s) - 1; w.inR2(s) && b >= w.rv && (w.r[b] == 's' || w.r[b] == 't') {
+			w.replace(s, "")
+		}
+	case "ier", "ière", "Ier", "Ière":
+		w.replace(s, "i")
+	case "e":
+		w.replace(s, "")
+	}
+}
+
+// frenchStep5 undoubles a final enn, onn, ett, ell or eill.
+func frenchStep5(w *word) {
+	if w.suffix("enn", "onn", "ett", "ell", "eill") != "" {
+		w.r = w.r[:len(w.r)-1]
+	}
+}
+
+// frenchStep6 unaccents é or è before the final non-vowels.
+func frenchStep6(w *word) {
+	i := len(w.r) - 1
+	for i >= 0 && !frenchVowel(w.r[i]) {
+		i--
+	}
+	if i >= 0 && i < len(w.r)-1 && (w.r[i] == 'é' || w.r[i] == 'è') {
+		w.r[i] = 'e'
+	}
+}
@@ -0,0 +1,98 @@
+package stemmer
+
+import "strings"
+
+// German Snowball stemmer.
+// See https://snowballstem.org/algorithms/german/stemmer.html
+
+var (
+	germanVowel = vowelSet("aeiouyäöü")
+
+	germanUmlauts = strings.NewReplacer("ä", "a", "ö", "o", "ü", "u", "U", "u", "Y", "y")
+)
+
+func germanSEnding(r rune) bool  { return strings.ContainsRune("bdfghklmnrt", r) }
+func germanSTEnding(r rune) bool { return strings.ContainsRune("bdfghklmnt", r) }
+
+// German returns the Snowball German stem of a lower case word.
+func German(s string) string {
+	w := &word{r: []rune(strings.ReplaceAll(s, "ß", "ss"))}
+
+	// Mark u and y between vowels as consonants
+	for i := 1; i < len(w.r)-1; i++ {
+		if (w.r[i] == 'u' || w.r[i] == 'y') && germanVowel(w.r[i-1]) && germanVowel(w.r[i+1]) {
+			w.r[i] = w.r[i] - 'a' + 'A'
+		}
+	}
+
+	w.markR1R2(germanVowel)
+	w.r1 = max(w.r1, 3) // At least 3 letters before R1
+
+	germanStep1(w)
+	germanStep2(w)
+	germanStep3(w)
+
+	return germanUmlauts.Replace(w.String())
+}
+
+func germanStep1(w *word) {
+	s := w.suffix("em", "ern", "er", "e", "en", "es", "s")
+	if s == "" || !w.inR1(s) {
+		return
+	}
+	switch s {
+	case "s":
+		if germanSEnding(w.before(s)) {
+			w.replace(s, "")
+		}
+	case "e", "en", "es":
+		w.replace(s, "")
+		if w.hasSuffix("niss") {
+			w.r = w.r[:len(w.r)-1]
+		}
+	default:
+		w.replace(s, "")
+	}
+}
+
+func germanStep2(w *word) {
+	s := w.suffix("en", "er", "est", "st")
+	if s == "" || !w.inR1(s) {
+		return
+	}
+	if s == "st" {
+		// Needs a valid st-ending, itself preceded by at least 3 letters
+		if !germanSTEnding(w.before(s)) || w.start(s) < 4 {
+			return
+		}
+	}
+	w.replace(s, "")
+}
+
+func germanStep3(w *word) {
+	s := w.suffix("end", "ung", "ig", "ik", "isch", "lich", "heit", "keit")
+	if s == "" || !w.inR2(s) {
+		return
+	}
+	switch s {
+	case "end", "ung":
+		w.replace(s, "")
+		if w.hasSuffix("ig") && w.inR2("ig") && w.before("ig") != 'e' {
+			w.replace("ig", "")
+		}
+	case "ig", "ik", "isch":
+		if w.before(s) != 'e' {
+			w.replace(s, "")
+		}
+	case "lich", "heit":
+		w.replace(s, "")
+		if p := w.suffix("er", "en"); p != "" && w.inR1(p) {
+			w.replace(p, "")
+		}
+	case "keit":
+		w.replace(s, "")
+		if p := w.suffix("lich", "ig"); p != "" && w.inR2(p) {
+			w.replace(p, "")
+		}
+	}
+}
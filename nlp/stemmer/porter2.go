@@ -0,0 +1,379 @@
+package stemmer
+
+import "strings"
+
+// Porter2 (Snowball English) stemmer.
+// See https://snowballstem.org/algorithms/english/stemmer.html
+
+var (
+	// Words that are stemmed to fixed forms or left alone
+	exceptions1 = map[string]string{
+		"skis":   "ski",
+		"skies":  "sky",
+		"dying":  "die",
+		"lying":  "lie",
+		"tying":  "tie",
+		"idly":   "idl",
+		"gently": "gentl",
+		"ugly":   "ugli",
+		"early":  "earli",
+		"only":   "onli",
+		"singly": "singl",
+		"sky":    "sky",
+		"news":   "news",
+		"howe":   "howe",
+		"atlas":  "atlas",
+		"cosmos": "cosmos",
+		"bias":   "bias",
+		"andes":  "andes",
+	}
+
+	// Words left alone after step 1a
+	exceptions2 = map[string]bool{
+		"inning":  true,
+		"outing":  true,
+		"canning": true,
+		"herring": true,
+		"earring": true,
+		"proceed": true,
+		"exceed":  true,
+		"succeed": true,
+	}
+
+	// Prefixes after which R1 starts, overriding the normal rule
+	r1Prefixes = []string{"gener", "commun", "arsen"}
+
+	step2Suffixes = []struct{ suffix, replace string }{
+		{"ization", "ize"},
+		{"ational", "ate"},
+		{"fulness", "ful"},
+		{"ousness", "ous"},
+		{"iveness", "ive"},
+		{"tional", "tion"},
+		{"biliti", "ble"},
+		{"lessli", "less"},
+		{"entli", "ent"},
+		{"ation", "ate"},
+		{"alism", "al"},
+		{"aliti", "al"},
+		{"ousli", "ous"},
+		{"iviti", "ive"},
+		{"fulli", "ful"},
+		{"enci", "ence"},
+		{"anci", "ance"},
+		{"abli", "able"},
+		{"izer", "ize"},
+		{"ator", "ate"},
+		{"alli", "al"},
+		{"bli", "ble"},
+		{"ogi", "og"},
+		{"li", ""},
+	}
+
+	step3Suffixes = []struct{ suffix, replace string }{
+		{"ational", "ate"},
+		{"tional", "tion"},
+		{"alize", "al"},
+		{"icate", "ic"},
+		{"iciti", "ic"},
+		{"ative", ""},
+		{"ical", "ic"},
+		{"ness", ""},
+		{"ful", ""},
+	}
+
+	step4Suffixes = []string{
+		"ement", "ance", "ence", "able", "ible", "ment",
+		"ant", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion",
+		"al", "er", "ic",
+	}
+)
+
+// Porter2 returns the Snowball English stem of a lower case word.
+func Porter2(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+
+	word = strings.ReplaceAll(word, "’", "'")
+	word = strings.TrimPrefix(word, "'")
+	if stem, ok := exceptions1[word]; ok {
+		return stem
+	}
+
+	w := &english{b: []byte(word)}
+	w.markYs()
+	w.findRegions()
+
+	w.step0()
+	w.step1a()
+	if exceptions2[string(w.b)] {
+		return string(w.b)
+	}
+	w.step1b()
+	w.step1c()
+	w.step2()
+	w.step3()
+	w.step4()
+	w.step5()
+
+	return strings.ReplaceAll(string(w.b), "Y", "y")
+}
+
+// english holds a word being stemmed and its R1/R2 region starts.
+type english struct {
+	b      []byte
+	r1, r2 int
+}
+
+func isVowel(c byte) bool {
+	switch c {
+	case 'a', 'e', 'i', 'o', 'u', 'y':
+		return true
+	}
+	return false
+}
+
+func isDouble(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	c := b[len(b)-1]
+	if c != b[len(b)-2] {
+		return false
+	}
+	switch c {
+	case 'b', 'd', 'f', 'g', 'm', 'n', 'p', 'r', 't':
+		return true
+	}
+	return false
+}
+
+func isLiEnding(c byte) bool {
+	return strings.IndexByte("cdeghkmnrt", c) >= 0
+}
+
+// markYs turns an initial y, and any y after a vowel, into a consonant Y.
+func (w *english) markYs() {
+	for i, c := range w.b {
+		if c == 'y' && (i == 0 || isVowel(w.b[i-1])) {
+			w.b[i] = 'Y'
+		}
+	}
+}
+
+// regionAfter returns the start of the region after the first non-vowel
+// following a vowel, starting the search at from.
+func regionAfter(b []byte, from int) int {
+	for i := from + 1; i < len(b); i++ {
+		if !isVowel(b[i]) && isVowel(b[i-1]) {
+			return i + 1
+		}
+	}
+	return len(b)
+}
+
+func (w *english) findRegions() {
+	w.r1 = -1
+	for _, p := range r1Prefixes {
+		if strings.HasPrefix(string(w.b), p) {
+			w.r1 = len(p)
+			break
+		}
+	}
+	if w.r1 < 0 {
+		w.r1 = regionAfter(w.b, 0)
+	}
+	w.r2 = regionAfter(w.b, w.r1)
+	if w.r1 >= len(w.b) {
+		w.r2 = len(w.b)
+	}
+}
+
+func (w *english) hasSuffix(s string) bool {
+	return strings.HasSuffix(string(w.b), s)
+}
+
+// inR1 reports whether a suffix of length n starts inside R1.
+func (w *english) inR1(n int) bool { return len(w.b)-n >= w.r1 }
+
+// inR2 reports whether a suffix of length n starts inside R2.
+func (w *english) inR2(n int) bool { return len(w.b)-n >= w.r2 }
+
+func (w *english) replace(n int, s string) {
+	w.b = append(w.b[:len(w.b)-n], s...)
+}
+
+// endsShortSyllable reports whether b ends in a short syllable: a non-vowel
+// other than w, x or Y preceded by a vowel preceded by a non-vowel, or a
+// vowel at the start of the word followed by a non-vowel.
+func endsShortSyllable(b []byte) bool {
+	n := len(b)
+	if n == 2 {
+		return isVowel(b[0]) && !isVowel(b[1])
+	}
+	if n >= 3 {
+		c := b[n-1]
+		return !isVowel(b[n-3]) && isVowel(b[n-2]) && !isVowel(c) && c != 'w' && c != 'x' && c != 'Y'
+	}
+	return false
+}
+
+// isShort reports whether the word ends in a short syllable and R1 is empty.
+func (w *english) isShort() bool {
+	return w.r1 >= len(w.b) && endsShortSyllable(w.b)
+}
+
+// step0 removes possessive suffixes.
+func (w *english) step0() {
+	for _, s := range []string{"'s'", "'s", "'"} {
+		if w.hasSuffix(s) {
+			w.replace(len(s), "")
+			return
+		}
+	}
+}
+
+// step1a handles plurals.
+func (w *english) step1a() {
+	switch {
+	case w.hasSuffix("sses"):
+		w.replace(2, "")
+	case w.hasSuffix("ied"), w.hasSuffix("ies"):
+		if len(w.b) > 4 {
+			w.replace(3, "i")
+		} else {
+			w.replace(3, "ie")
+		}
+	case w.hasSuffix("us"), w.hasSuffix("ss"):
+		// Leave alone
+	case w.hasSuffix("s"):
+		// Delete if a vowel appears before the letter preceding the s
+		for i := 0; i < len(w.b)-2; i++ {
+			if isVowel(w.b[i]) {
+				w.replace(1, "")
+				return
+			}
+		}
+	}
+}
+
+// step1b handles -ed and -ing endings.
+func (w *english) step1b() {
+	for _, s := range []string{"eedly", "eed"} {
+		if w.hasSuffix(s) {
+			if w.inR1(len(s)) {
+				w.replace(len(s), "ee")
+			}
+			return
+		}
+	}
+
+	for _, s := range []string{"ingly", "edly", "ing", "ed"} {
+		if !w.hasSuffix(s) {
+			continue
+		}
+		stem := w.b[:len(w.b)-len(s)]
+		hasVowel := false
+		for _, c := range stem {
+			if isVowel(c) {
+				hasVowel = true
+				break
+			}
+		}
+		if !hasVowel {
+			return
+		}
+
+		w.replace(len(s), "")
+		switch {
+		case w.hasSuffix("at"), w.hasSuffix("bl"), w.hasSuffix("iz"):
+			w.b = append(w.b, 'e')
+		case isDouble(w.b):
+			w.b = w.b[:len(w.b)-1]
+		case w.isShort():
+			w.b = append(w.b, 'e')
+		}
+		return
+	}
+}
+
+// step1c turns a final y into i after a non-vowel that isn't the first letter.
+func (w *english) step1c() {
+	n := len(w.b)
+	if n > 2 && (w.b[n-1] == 'y' || w.b[n-1] == 'Y') && !isVowel(w.b[n-2]) {
+		w.b[n-1] = 'i'
+	}
+}
+
+func (w *english) step2() {
+	for _, s := range step2Suffixes {
+		if !w.hasSuffix(s.suffix) {
+			continue
+		}
+		if !w.inR1(len(s.suffix)) {
+			return
+		}
+		n := len(w.b) - len(s.suffix)
+		switch s.suffix {
+		case "ogi":
+			if n == 0 || w.b[n-1] != 'l' {
+				return
+			}
+		case "li":
+			if n == 0 || !isLiEnding(w.b[n-1]) {
+				return
+			}
+		}
+		w.replace(len(s.suffix), s.replace)
+		return
+	}
+}
+
+func (w *english) step3() {
+	for _, s := range step3Suffixes {
+		if !w.hasSuffix(s.suffix) {
+			continue
+		}
+		if !w.inR1(len(s.suffix)) {
+			return
+		}
+		if s.suffix == "ative" && !w.inR2(len(s.suffix)) {
+			return
+		}
+		w.replace(len(s.suffix), s.replace)
+		return
+	}
+}
+
+func (w *english) step4() {
+	for _, s := range step4Suffixes {
+		if !w.hasSuffix(s) {
+			continue
+		}
+		if !w.inR2(len(s)) {
+			return
+		}
+		if s == "ion" {
+			n := len(w.b) - len(s)
+			if n == 0 || (w.b[n-1] != 's' && w.b[n-1] != 't') {
+				return
+			}
+		}
+		w.replace(len(s), "")
+		return
+	}
+}
+
+func (w *english) step5() {
+	switch {
+	case w.hasSuffix("e"):
+		if w.inR2(1) || (w.inR1(1) && !endsShortSyllable(w.b[:len(w.b)-1])) {
+			w.replace(1, "")
+		}
+	case w.hasSuffix("l"):
+		if w.inR2(1) && len(w.b) > 1 && w.b[len(w.b)-2] == 'l' {
+			w.replace(1, "")
+		}
+	}
+}
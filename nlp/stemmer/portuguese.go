@@ -0,0 +1,150 @@
+package stemmer
+
+import "strings"
+
+// Portuguese Snowball stemmer.
+// See https://snowballstem.org/algorithms/portuguese/stemmer.html
+
+var (
+	portugueseVowel = vowelSet("aeiouáéíóúâêô")
+
+	// Nasal vowels are written as a vowel and ~ while stemming
+	portuguesePrelude  = strings.NewReplacer("ã", "a~", "õ", "o~")
+	portuguesePostlude = strings.NewReplacer("a~", "ã", "o~", "õ")
+
+	portugueseVerbSuffixes = []string{
+		"ada", "ida", "ia", "aria", "eria", "iria", "ará", "ara", "erá",
+		"era", "irá", "ava", "asse", "esse", "isse", "aste", "este", "iste",
+		"ei", "arei", "erei", "irei", "am", "iam", "ariam", "eriam", "iriam",
+		"aram", "eram", "iram", "avam", "em", "arem", "erem", "irem", "assem",
+		"essem", "issem", "ado", "ido", "ando", "endo", "indo", "ara~o",
+		"era~o", "ira~o", "ar", "er", "ir", "as", "adas", "idas", "ias",
+		"arias", "erias", "irias", "arás", "aras", "erás", "eras",
+		"irás", "avas", "es", "ardes", "erdes", "irdes", "ares", "eres",
+		"ires", "asses", "esses", "isses", "astes", "estes", "istes", "is",
+		"ais", "eis", "íeis", "aríeis", "eríeis", "iríeis",
+		"áreis", "areis", "éreis", "ereis", "íreis", "ireis",
+		"ásseis", "ésseis", "ísseis", "áveis", "ados", "idos",
+		"ámos", "amos", "íamos", "aríamos", "eríamos",
+		"iríamos", "áramos", "éramos", "íramos", "ávamos",
+		"emos", "aremos", "eremos", "iremos", "ássemos", "êssemos",
+		"íssemos", "imos", "armos", "ermos", "irmos", "eu", "iu", "ou",
+		"ira", "iras",
+	}
+)
+
+// Portuguese returns the Snowball Portuguese stem of a lower case word.
+func Portuguese(s string) string {
+	w := &word{r: []rune(portuguesePrelude.Replace(s))}
+	w.markRomanceRV(portugueseVowel)
+	w.markR1R2(portugueseVowel)
+
+	if portugueseStep1(w) || portugueseStep2(w) {
+		// Step 3
+		if w.hasSuffix("ci") && w.inRV("i") {
+			w.r = w.r[:len(w.r)-1]
+		}
+	} else {
+		// Step 4
+		if s := w.suffix("os", "a", "i", "o", "á", "í", "ó"); s != "" && w.inRV(s) {
+			w.replace(s, "")
+		}
+	}
+	portugueseStep5(w)
+
+	return portuguesePostlude.Replace(w.String())
+}
+
+// portugueseStep1 removes standard suffixes and reports whether it did.
+func portugueseStep1(w *word) bool {
+	s := w.suffix(
+		"eza", "ezas", "ico", "ica", "icos", "icas", "ismo", "ismos", "ável", "ível",
+		"ista", "istas", "oso", "osa", "osos", "osas", "amento", "amentos", "imento", "imentos",
+		"adora", "ador", "aça~o", "adoras", "adores", "aço~es", "ante", "antes", "ância",
+		"logia", "logias", "uça~o", "uço~es", "ência", "ências",
+		"amente", "mente", "idade", "idades", "iva", "ivo", "ivas", "ivos", "ira", "iras",
+	)
+
+	switch s {
+	case "":
+		return false
+	case "amente":
+		if !w.inR1(s) {
+			return false
+		}
+		w.replace(s, "")
+		if p := w.suffix("iv", "os", "ic", "ad"); p != "" && w.inR2(p) {
+			w.replace(p, "")
+			if p == "iv" && w.hasSuffix("at") && w.inR2("at") {
+				w.replace("at", "")
+			}
+		}
+		return true
+	case "ira", "iras":
+		if !w.inRV(s) || w.before(s) != 'e' {
+			return false
+		}
+		w.replace(s, "ir")
+		return true
+	}
+
+	if !w.inR2(s) {
+		return false
+	}
+	switch s {
+	case "logia", "logias":
+		w.replace(s, "log")
+	case "uça~o", "uço~es":
+		w.replace(s, "u")
+	case "ência", "ências":
+		w.replace(s, "ente")
+	case "mente":
+		w.replace(s, "")
+		if p := w.suffix("ante", "avel", "ível"); p != "" && w.inR2(p) {
+			w.replace(p, "")
+		}
+	case "idade", "idades":
+		w.replace(s, "")
+		if p := w.suffix("abil", "ic", "iv"); p != "" && w.inR2(p) {
+			w.replace(p, "")
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		w.replace(s, "")
+		if w.hasSuffix("at") && w.inR2("at") {
+			w.replace("at", "")
+		}
+	default:
+		w.replace(s, "")
+	}
+	return true
+}
+
+// portugueseStep2 removes verb suffixes and reports whether it did.
+func portugueseStep2(w *word) bool {
+	s := w.suffixIn(w.rv, portugueseVerbSuffixes...)
+	if s == "" {
+		return false
+	}
+	w.replace(s, "")
+	return true
+}
+
+// portugueseStep5 removes a residual e and turns a final ç into c.
+func portugueseStep5(w *word) {
+	s := w.suffix("e", "é", "ê", "ç")
+	switch s {
+	case "":
+		return
+	case "ç":
+		w.replace(s, "c")
+		return
+	}
+	if !w.inRV(s) {
+		return
+	}
+	w.replace(s, "")
+	// The u of gu or the i of ci goes too, if in RV
+	if (w.hasSuffix("gu") || w.hasSuffix("ci")) && len(w.r)-1 >= w.rv {
+		w.r = w.r[:len(w.r)-1]
+	}
+}
@@ -0,0 +1,66 @@
+package stemmer
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Stemmer reduces words to their stems.
+type Stemmer interface {
+	Stem(word string) string
+}
+
+// Func adapts a stemming function to the Stemmer interface.
+type Func func(word string) string
+
+// Stem calls f(word).
+func (f Func) Stem(word string) string {
+	return f(word)
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Stemmer{
+		"en": Func(Stem),
+		"de": lowered(German),
+		"fr": lowered(French),
+		"es": lowered(Spanish),
+		"pt": lowered(Portuguese),
+	}
+)
+
+// lowered lower cases words before passing them to fn.
+func lowered(fn func(string) string) Func {
+	return func(word string) string {
+		return fn(strings.ToLower(word))
+	}
+}
+
+// Register makes a stemmer available for a language code (e.g. "it"),
+// replacing any existing one.
+func Register(lang string, s Stemmer) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[strings.ToLower(lang)] = s
+}
+
+// ForLanguage returns the stemmer for a language code such as "en" or "de".
+func ForLanguage(lang string) (Stemmer, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	s, ok := registry[strings.ToLower(lang)]
+	return s, ok
+}
+
+// Languages returns the registered language codes, sorted.
+func Languages() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	langs := make([]string, 0, len(registry))
+	for lang := range registry {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
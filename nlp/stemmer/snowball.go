@@ -0,0 +1,128 @@
+package stemmer
+
+import "strings"
+
+// Helpers shared by the rune based Snowball stemmers (German, French,
+// Spanish and Portuguese).
+
+// word is a word being stemmed, with the start of its RV, R1 and R2 regions.
+type word struct {
+	r          []rune
+	rv, r1, r2 int
+}
+
+func (w *word) String() string { return string(w.r) }
+
+// suffix returns the longest of suffixes that w ends with, or "".
+func (w *word) suffix(suffixes ...string) string {
+	longest := ""
+	for _, s := range suffixes {
+		if len(s) > len(longest) && w.hasSuffix(s) {
+			longest = s
+		}
+	}
+	return longest
+}
+
+// suffixIn returns the longest of suffixes that w ends with and that start
+// at or after index from, or "". Snowball steps limited to a region
+// (setlimit) match this way, so a longer suffix reaching out of the region
+// doesn't hide a shorter one inside it.
+func (w *word) suffixIn(from int, suffixes ...string) string {
+	longest := ""
+	for _, s := range suffixes {
+		if len(s) > len(longest) && w.hasSuffix(s) && w.start(s) >= from {
+			longest = s
+		}
+	}
+	return longest
+}
+
+func (w *word) hasSuffix(s string) bool {
+	return strings.HasSuffix(string(w.r), s)
+}
+
+// start returns the index at which suffix s (assumed present) starts.
+func (w *word) start(s string) int {
+	return len(w.r) - len([]rune(s))
+}
+
+func (w *word) inRV(s string) bool { return w.start(s) >= w.rv }
+func (w *word) inR1(s string) bool { return w.start(s) >= w.r1 }
+func (w *word) inR2(s string) bool { return w.start(s) >= w.r2 }
+
+// replace replaces suffix s (assumed present) with repl.
+func (w *word) replace(s, repl string) {
+	w.r = append(w.r[:w.start(s)], []rune(repl)...)
+}
+
+// trim deletes suffix s if w ends with it, and reports whether it did.
+func (w *word) trim(s string) bool {
+	if !w.hasSuffix(s) {
+		return false
+	}
+	w.replace(s, "")
+	return true
+}
+
+// before returns the rune before suffix s (assumed present), or 0.
+func (w *word) before(s string) rune {
+	i := w.start(s) - 1
+	if i < 0 {
+		return 0
+	}
+	return w.r[i]
+}
+
+// runeRegionAfter returns the index after the first non-vowel following a
+// vowel, searching from index from.
+func runeRegionAfter(r []rune, from int, isVowel func(rune) bool) int {
+	for i := from + 1; i < len(r); i++ {
+		if !isVowel(r[i]) && isVowel(r[i-1]) {
+			return i + 1
+		}
+	}
+	return len(r)
+}
+
+// markR1R2 sets the standard R1 and R2 regions.
+func (w *word) markR1R2(isVowel func(rune) bool) {
+	w.r1 = runeRegionAfter(w.r, 0, isVowel)
+	w.r2 = runeRegionAfter(w.r, w.r1, isVowel)
+}
+
+// markRomanceRV sets RV as defined for Spanish and Portuguese: after the
+// next vowel if the second letter is a consonant, after the next consonant
+// if the first two letters are vowels, otherwise after the third letter.
+func (w *word) markRomanceRV(isVowel func(rune) bool) {
+	r := w.r
+	w.rv = len(r)
+	if len(r) < 2 {
+		return
+	}
+
+	next := func(from int, vowel bool) int {
+		for i := from; i < len(r); i++ {
+			if isVowel(r[i]) == vowel {
+				return i + 1
+			}
+		}
+		return len(r)
+	}
+
+	switch {
+	case !isVowel(r[1]):
+		w.rv = next(2, true)
+	case isVowel(r[0]):
+		w.rv = next(2, false)
+	default:
+		w.rv = min(3, len(r))
+	}
+}
+
+// vowelSet returns a vowel test for the runes in s.
+func vowelSet(s string) func(rune) bool {
+	return func(r rune) bool {
+		return strings.ContainsRune(s, r)
+	}
+}
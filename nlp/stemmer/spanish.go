@@ -0,0 +1,174 @@
+package stemmer
+
+import "strings"
+
+// Spanish Snowball stemmer.
+// See https://snowballstem.org/algorithms/spanish/stemmer.html
+
+var (
+	spanishVowel = vowelSet("aeiouáéíóúü")
+
+	spanishUnaccent = strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u")
+
+	spanishPronouns = []string{
+		"me", "se", "sela", "selo", "selas", "selos", "la", "le", "lo", "las", "les", "los", "nos",
+	}
+
+	spanishYVerbSuffixes = []string{
+		"ya", "ye", "yan", "yen", "yeron", "yendo", "yo", "yó", "yas", "yes", "yais", "yamos",
+	}
+
+	spanishVerbSuffixes = []string{
+		"en", "es", "éis", "emos",
+		"arían", "arías", "arán", "arás", "aríais", "aría", "aréis", "aríamos", "aremos", "ará", "aré",
+		"erían", "erías", "erán", "erás", "eríais", "ería", "eréis", "eríamos", "eremos", "erá", "eré",
+		"irían", "irías", "irán", "irás", "iríais", "iría", "iréis", "iríamos", "iremos", "irá", "iré",
+		"aba", "ada", "ida", "ía", "ara", "iera", "ad", "ed", "id", "ase", "iese", "aste", "iste",
+		"an", "aban", "ían", "aran", "ieran", "asen", "iesen", "aron", "ieron", "ado", "ido",
+		"ando", "iendo", "ió", "ar", "er", "ir", "as", "abas", "adas", "idas", "ías", "aras", "ieras",
+		"ases", "ieses", "ís", "áis", "abais", "íais", "arais", "ierais", "aseis", "ieseis", "asteis",
+		"isteis", "ados", "idos", "amos", "ábamos", "íamos", "imos", "áramos", "iéramos", "iésemos", "ásemos",
+	}
+)
+
+// Spanish returns the Snowball Spanish stem of a lower case word.
+func Spanish(s string) string {
+	w := &word{r: []rune(s)}
+	w.markRomanceRV(spanishVowel)
+	w.markR1R2(spanishVowel)
+
+	spanishPronoun(w)
+	if !spanishStep1(w) && !spanishStep2a(w) {
+		spanishStep2b(w)
+	}
+	spanishStep3(w)
+
+	return spanishUnaccent.Replace(w.String())
+}
+
+// spanishPronoun removes attached pronouns after gerunds and infinitives.
+func spanishPronoun(w *word) {
+	p := w.suffix(spanishPronouns...)
+	if p == "" {
+		return
+	}
+
+	verb := &word{r: w.r[:w.start(p)]}
+	s := verb.suffix("iéndo", "ándo", "ár", "ér", "ír", "ando", "iendo", "ar", "er", "ir", "yendo")
+	if s == "" || verb.start(s) < w.rv {
+		return
+	}
+
+	switch s {
+	case "yendo":
+		if verb.before(s) != 'u' {
+			return
+		}
+		w.replace(p, "")
+	case "iéndo", "ándo", "ár", "ér", "ír":
+		w.replace(p, "")
+		w.replace(s, spanishUnaccent.Replace(s))
+	default:
+		w.replace(p, "")
+	}
+}
+
+// spanishStep1 removes standard suffixes and reports whether it did.
+func spanishStep1(w *word) bool {
+	s := w.suffix(
+		"anza", "anzas", "ico", "ica", "icos", "icas", "ismo", "ismos", "able", "ables", "ible", "ibles",
+		"ista", "istas", "oso", "osa", "osos", "osas", "amiento", "amientos", "imiento", "imientos",
+		"adora", "ador", "ación", "adoras", "adores", "aciones", "ante", "antes", "ancia", "ancias",
+		"logía", "logías", "ución", "uciones", "encia", "encias",
+		"amente", "mente", "idad", "idades", "iva", "ivo", "ivas", "ivos",
+	)
+
+	switch s {
+	case "":
+		return false
+	case "amente":
+		if !w.inR1(s) {
+			return false
+		}
+		w.replace(s, "")
+		if p := w.suffix("iv", "os", "ic", "ad"); p != "" && w.inR2(p) {
+			w.replace(p, "")
+			if p == "iv" && w.hasSuffix("at") && w.inR2("at") {
+				w.replace("at", "")
+			}
+		}
+		return true
+	}
+
+	if !w.inR2(s) {
+		return false
+	}
+	switch s {
+	case "adora", "ador", "ación", "adoras", "adores", "aciones", "ante", "antes", "ancia", "ancias":
+		w.replace(s, "")
+		if w.hasSuffix("ic") && w.inR2("ic") {
+			w.replace("ic", "")
+		}
+	case "logía", "logías":
+		w.replace(s, "log")
+	case "ución", "uciones":
+		w.replace(s, "u")
+	case "encia", "encias":
+		w.replace(s, "ente")
+	case "mente":
+		w.replace(s, "")
+		if p := w.suffix("ante", "able", "ible"); p != "" && w.inR2(p) {
+			w.replace(p, "")
+		}
+	case "idad", "idades":
+		w.replace(s, "")
+		if p := w.suffix("abil", "ic", "iv"); p != "" && w.inR2(p) {
+			w.replace(p, "")
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		w.replace(s, "")
+		if w.hasSuffix("at") && w.inR2("at") {
+			w.replace("at", "")
+		}
+	default:
+		w.replace(s, "")
+	}
+	return true
+}
+
+// spanishStep2a removes verb suffixes beginning with y after a u.
+func spanishStep2a(w *word) bool {
+	s := w.suffixIn(w.rv, spanishYVerbSuffixes...)
+	if s == "" || w.before(s) != 'u' {
+		return false
+	}
+	w.replace(s, "")
+	return true
+}
+
+// spanishStep2b removes the other verb suffixes.
+func spanishStep2b(w *word) {
+	s := w.suffixIn(w.rv, spanishVerbSuffixes...)
+	if s == "" {
+		return
+	}
+	w.replace(s, "")
+	switch s {
+	case "en", "es", "éis", "emos":
+		if w.hasSuffix("gu") {
+			w.r = w.r[:len(w.r)-1]
+		}
+	}
+}
+
+// spanishStep3 removes residual suffixes.
+func spanishStep3(w *word) {
+	s := w.suffix("os", "a", "o", "á", "í", "ó", "e", "é")
+	if s == "" || !w.inRV(s) {
+		return
+	}
+	w.replace(s, "")
+	if (s == "e" || s == "é") && w.hasSuffix("gu") && w.inRV("u") {
+		w.r = w.r[:len(w.r)-1]
+	}
+}
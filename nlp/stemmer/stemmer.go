@@ -8,7 +8,14 @@ var (
 	suffixes = []string{"s", "ing", "ed"}
 )
 
+// Stem returns the English stem of word, using the Porter2 algorithm.
 func Stem(word string) string {
+	return Porter2(strings.ToLower(word))
+}
+
+// Naive strips the first matching suffix of "s", "ing" and "ed".
+// It is the original, much cruder, stemmer: "sing" becomes "s".
+func Naive(word string) string {
 	for _, suffix := range suffixes {
 		if strings.HasSuffix(word, suffix) {
 			return word[:len(word)-len(suffix)]
@@ -0,0 +1,71 @@
+package stemmer
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// checkVocabulary checks stem against a file of "word stem" lines.
+func checkVocabulary(t *testing.T, path string, stem func(string) string) {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	s := bufio.NewScanner(file)
+	for s.Scan() {
+		line := s.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		require.Len(t, fields, 2, "bad line: %q", line)
+		word, expected := fields[0], fields[1]
+		require.Equal(t, expected, stem(word), word)
+	}
+	require.NoError(t, s.Err())
+}
+
+func TestPorter2Vocabulary(t *testing.T) {
+	checkVocabulary(t, "../testdata/porter2.txt", Stem)
+}
+
+func TestSnowballVocabulary(t *testing.T) {
+	for _, lang := range []string{"de", "fr", "es", "pt"} {
+		t.Run(lang, func(t *testing.T) {
+			s, ok := ForLanguage(lang)
+			require.True(t, ok)
+			checkVocabulary(t, "../testdata/snowball_"+lang+".txt", s.Stem)
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	require.Equal(t, []string{"de", "en", "es", "fr", "pt"}, Languages())
+
+	s, ok := ForLanguage("DE")
+	require.True(t, ok)
+	require.Equal(t, "kauf", s.Stem("Käufer"))
+
+	_, ok = ForLanguage("xx")
+	require.False(t, ok)
+
+	Register("xx", Func(strings.ToUpper))
+	defer func() {
+		registryMu.Lock()
+		delete(registry, "xx")
+		registryMu.Unlock()
+	}()
+	s, ok = ForLanguage("xx")
+	require.True(t, ok)
+	require.Equal(t, "GO", s.Stem("go"))
+}
+
+func TestNaive(t *testing.T) {
+	require.Equal(t, "work", Naive("working"))
+	require.Equal(t, "s", Naive("sing"))
+	require.Equal(t, "bu", Naive("bus"))
+}
@@ -0,0 +1,73 @@
+package nlp
+
+import (
+	"bufio"
+	"embed"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Stop word lists, stopwords/<lang>.txt with one word per line and # comments
+//
+//go:embed stopwords/*.txt
+var stopWordFiles embed.FS
+
+// StopWordList returns the built-in stop words for a language code such as
+// "en" or "de", for use with the StopWords filter.
+func StopWordList(lang string) ([]string, bool) {
+	file, err := stopWordFiles.Open("stopwords/" + strings.ToLower(lang) + ".txt")
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	words, err := readStopWords(file)
+	if err != nil {
+		return nil, false
+	}
+	return words, true
+}
+
+// StopWordLanguages returns the language codes with a built-in stop word
+// list, sorted.
+func StopWordLanguages() []string {
+	entries, _ := stopWordFiles.ReadDir("stopwords")
+	var langs []string
+	for _, e := range entries {
+		langs = append(langs, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// LoadStopWords reads a custom stop word list from a file, in the format of
+// the built-in lists: one word per line, empty lines and lines starting with
+// # are ignored.
+func LoadStopWords(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readStopWords(file)
+}
+
+func readStopWords(r io.Reader) ([]string, error) {
+	var words []string
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
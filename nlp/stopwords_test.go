@@ -0,0 +1,51 @@
+package nlp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopWordList(t *testing.T) {
+	require.Equal(t, []string{"de", "en", "es", "fr", "pt"}, StopWordLanguages())
+
+	for _, lang := range StopWordLanguages() {
+		words, ok := StopWordList(lang)
+		require.True(t, ok, lang)
+		require.NotEmpty(t, words, lang)
+		require.NotContains(t, words, "", lang)
+	}
+
+	words, ok := StopWordList("EN")
+	require.True(t, ok)
+	require.Contains(t, words, "the")
+	require.Contains(t, words, "don't")
+
+	_, ok = StopWordList("xx")
+	require.False(t, ok)
+}
+
+func TestStopWordsFilter(t *testing.T) {
+	en, _ := StopWordList("en")
+	a := NewAnalyzer(Lowercase(), StopWords(en...))
+	require.Equal(t, []string{"adventure", "sherlock", "holmes", "know"}, a.Terms("The Adventure of Sherlock Holmes, and I don't know"))
+
+	de, _ := StopWordList("de")
+	a = NewAnalyzer(Lowercase(), StopWords(de...))
+	require.Equal(t, []string{"katze"}, a.Terms("Über die Katze"))
+}
+
+func TestLoadStopWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stop.txt")
+	err := os.WriteFile(path, []byte("# Custom list\nwatson\n\n  holmes \n"), 0o644)
+	require.NoError(t, err)
+
+	words, err := LoadStopWords(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"watson", "holmes"}, words)
+
+	_, err = LoadStopWords(filepath.Join(t.TempDir(), "missing.txt"))
+	require.Error(t, err)
+}
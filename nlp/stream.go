@@ -0,0 +1,162 @@
+package nlp
+
+import (
+	"io"
+	"iter"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultBufferSize is the default buffer size of a Tokenizer.
+const DefaultBufferSize = 64 * 1024
+
+// Tokenizer reads text from an io.Reader and yields its tokens, using a
+// fixed size buffer so that large inputs are tokenized in constant memory.
+// Offsets and positions of the tokens are from the start of the input.
+//
+// Like bufio.Scanner, iterating stops at the first read error, after the
+// tokens read before it, and the error is then returned by Err.
+//
+//	tok := NewTokenizer(file)
+//	for term := range tok.Terms() {
+//		...
+//	}
+//	if err := tok.Err(); err != nil {
+//		...
+//	}
+type Tokenizer struct {
+	r        io.Reader
+	analyzer *Analyzer
+	buf      []byte
+	err      error
+}
+
+// NewTokenizer returns a Tokenizer reading from r, with the same options as
+// Tokenize.
+func NewTokenizer(r io.Reader, opts ...Option) *Tokenizer {
+	return &Tokenizer{
+		r:        r,
+		analyzer: newOptions(opts).newAnalyzer(),
+		buf:      make([]byte, DefaultBufferSize),
+	}
+}
+
+// Buffer sets the buffer size, words (with the spaces before them) longer
+// than the buffer are split. It must be called before iterating.
+func (t *Tokenizer) Buffer(size int) {
+	t.buf = make([]byte, max(size, utf8.UTFMax))
+}
+
+// Err returns the first read error, other than io.EOF.
+func (t *Tokenizer) Err() error {
+	return t.err
+}
+
+// Tokens returns an iterator over the tokens of the input. The input can be
+// iterated only once.
+func (t *Tokenizer) Tokens() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		var (
+			n        int  // Bytes in buf
+			offset   int  // Input offset of buf
+			runes    int  // Input rune offset of buf
+			position int  // Position of the first word in buf
+			eof      bool // Reading is done
+		)
+		for !eof || n > 0 {
+			if !eof {
+				m, err := io.ReadFull(t.r, t.buf[n:])
+				n += m
+				switch err {
+				case nil:
+				case io.EOF, io.ErrUnexpectedEOF:
+					eof = true
+				default:
+					// Yield the tokens of what was read before the error
+					t.err = err
+					eof = true
+				}
+			}
+
+			cut := n
+			if !eof {
+				cut = safeCut(t.buf[:n])
+			}
+
+			text := string(t.buf[:cut])
+			tokens := t.analyzer.split(text)
+			for i := range tokens {
+				tok := &tokens[i]
+				tok.Start += offset
+				tok.End += offset
+				tok.RuneStart += runes
+				tok.RuneEnd += runes
+				tok.Position += position
+			}
+			position += len(tokens)
+			offset += cut
+			runes += utf8.RuneCountInString(text)
+
+			for _, tok := range t.analyzer.filter(tokens) {
+				if !yield(tok) {
+					return
+				}
+			}
+
+			n = copy(t.buf, t.buf[cut:n])
+		}
+	}
+}
+
+// Terms returns an iterator over the terms (Stem field) of the tokens.
+func (t *Tokenizer) Terms() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for tok := range t.Tokens() {
+			if !yield(tok.Stem) {
+				return
+			}
+		}
+	}
+}
+
+// safeCut returns where to cut b so that the word boundaries before the cut
+// do not depend on what follows it, which is the case before the last white
+// space that follows a non space, or before the last ideograph (text without
+// spaces). If there is no such place, b is cut after its last complete rune.
+func safeCut(b []byte) int {
+	for i := len(b); i > 0; {
+		r, size := utf8.DecodeLastRune(b[:i])
+		i -= size
+		if i == 0 {
+			break
+		}
+		if r < utf8.RuneSelf && isASCIISpace(byte(r)) && !isASCIISpace(b[i-1]) {
+			return i
+		}
+		if unicode.In(r, unicode.Han, unicode.Hiragana) {
+			return i
+		}
+	}
+
+	i := len(b)
+	for j := len(b) - 1; j >= 0 && j >= len(b)-utf8.UTFMax; j-- {
+		if utf8.RuneStart(b[j]) {
+			if !utf8.FullRune(b[j:]) {
+				i = j
+			}
+			break
+		}
+	}
+	if i == 0 {
+		return len(b) // Invalid UTF-8, can't do better
+	}
+	return i
+}
+
+func isASCIISpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\v', '\f', '\r':
+		return true
+	}
+	return false
+}
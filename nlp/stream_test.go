@@ -0,0 +1,92 @@
+package nlp
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+const streamText = `Who's on first? What's on second!
+Les élèves continuaient à l'école, naïve café 3.14 1,000
+日本語のテキスト   Привет, мир! rock’n’roll   e.g. U.S.A.`
+
+func collect(tok *Tokenizer) []Token {
+	var tokens []Token
+	for t := range tok.Tokens() {
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+func TestTokenizerBuffers(t *testing.T) {
+	expected := TokenizeDetailed(streamText)
+	for size := 24; size <= 80; size++ { // Longest word is 15 bytes
+		tok := NewTokenizer(strings.NewReader(streamText))
+		tok.Buffer(size)
+		tokens := collect(tok)
+		require.NoError(t, tok.Err())
+		require.Equal(t, expected, tokens, "buffer size %d", size)
+	}
+}
+
+func TestTokenizerOptions(t *testing.T) {
+	a, _ := Preset("search")
+	tok := NewTokenizer(iotest.OneByteReader(strings.NewReader(streamText)), WithAnalyzer(a))
+	tok.Buffer(32)
+	require.Equal(t, a.Analyze(streamText), collect(tok))
+
+	tok = NewTokenizer(strings.NewReader("Die Käufer kaufen"), WithLanguage("de"))
+	var terms []string
+	for term := range tok.Terms() {
+		terms = append(terms, term)
+	}
+	require.Equal(t, []string{"die", "kauf", "kauf"}, terms)
+}
+
+func TestTokenizerStop(t *testing.T) {
+	tok := NewTokenizer(strings.NewReader(streamText))
+	var terms []string
+	for term := range tok.Terms() {
+		terms = append(terms, term)
+		if len(terms) == 2 {
+			break
+		}
+	}
+	require.Equal(t, []string{"who", "on"}, terms)
+}
+
+func TestTokenizerError(t *testing.T) {
+	errBoom := errors.New("boom")
+	r := io.MultiReader(strings.NewReader("who on first "), iotest.ErrReader(errBoom))
+	tok := NewTokenizer(r)
+	tok.Buffer(8)
+	var terms []string
+	for term := range tok.Terms() {
+		terms = append(terms, term)
+	}
+	require.ErrorIs(t, tok.Err(), errBoom)
+	require.Equal(t, []string{"who", "on", "first"}, terms)
+}
+
+func FuzzTokenizer(f *testing.F) {
+	f.Add(streamText, 5)
+	f.Fuzz(func(t *testing.T, text string, size int) {
+		size = 4 + (size%64+64)%64
+		tok := NewTokenizer(strings.NewReader(text))
+		tok.Buffer(size)
+		tokens := collect(tok)
+		require.NoError(t, tok.Err())
+
+		// Words longer than the buffer are split
+		for _, w := range strings.Fields(text) {
+			if 2*len(w) >= size {
+				return
+			}
+		}
+		require.Equal(t, TokenizeDetailed(text), tokens)
+	})
+}
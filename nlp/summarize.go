@@ -0,0 +1,75 @@
+package nlp
+
+import (
+	"math"
+	"sort"
+)
+
+// Summarize returns the n most central sentences of text, in text order, as
+// an extractive summary. Sentences are ranked with TextRank: they are the
+// vertices of a graph, linked by the similarity of their terms (from
+// Tokenize, without stop words), and ranked with PageRank over the graph.
+// If text has n sentences or less, all are returned.
+func Summarize(text string, n int) []Sentence {
+	sentences := Sentences(text)
+	if n <= 0 {
+		return nil
+	}
+	if len(sentences) <= n {
+		return sentences
+	}
+
+	a, _ := Preset("search")
+	terms := make([]map[string]bool, len(sentences))
+	for i, s := range sentences {
+		terms[i] = make(map[string]bool)
+		for _, term := range Tokenize(s.Text, WithAnalyzer(a)) {
+			terms[i][term] = true
+		}
+	}
+
+	links := make([]map[int]float64, len(sentences))
+	for i := range links {
+		links[i] = make(map[int]float64)
+	}
+	for i := range sentences {
+		for j := i + 1; j < len(sentences); j++ {
+			if sim := sentenceSimilarity(terms[i], terms[j]); sim > 0 {
+				links[i][j] = sim
+				links[j][i] = sim
+			}
+		}
+	}
+
+	ranks := pageRank(links)
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return ranks[order[i]] > ranks[order[j]] })
+	order = order[:n]
+	sort.Ints(order)
+
+	summary := make([]Sentence, n)
+	for i, idx := range order {
+		summary[i] = sentences[idx]
+	}
+	return summary
+}
+
+// sentenceSimilarity returns the similarity of two sentences of the TextRank
+// paper: the number of terms they share, normalized by the log of their
+// lengths so long sentences are not favored.
+func sentenceSimilarity(a, b map[string]bool) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 0 // log(1) is 0
+	}
+
+	shared := 0
+	for term := range a {
+		if b[term] {
+			shared++
+		}
+	}
+	return float64(shared) / (math.Log(float64(len(a))) + math.Log(float64(len(b))))
+}
@@ -0,0 +1,46 @@
+package nlp
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeSherlock(t *testing.T) {
+	data, err := os.ReadFile("../freq/sherlock.txt")
+	require.NoError(t, err)
+	text := string(data)
+	start := indexOf(t, text, "V. THE FIVE ORANGE PIPS")
+	end := indexOf(t, text, "VI. THE MAN WITH THE TWISTED LIP")
+	chapter := text[start:end]
+
+	summary := Summarize(chapter, 5)
+	require.Len(t, summary, 5)
+	for i, s := range summary {
+		require.Equal(t, s.Text, chapter[s.Start:s.End])
+		if i > 0 {
+			require.Greater(t, s.Start, summary[i-1].End, "text order")
+		}
+		t.Log(strings.Join(strings.Fields(s.Text), " "))
+	}
+	require.Equal(t, summary, Summarize(chapter, 5))
+
+	joined := ""
+	for _, s := range summary {
+		joined += s.Text
+	}
+	require.Contains(t, joined, "Openshaw")
+}
+
+func TestSummarize(t *testing.T) {
+	text := "The cat sat on the mat. The dog chased the cat around the mat. It rained. The dog and the cat slept on the mat."
+	summary := Summarize(text, 2)
+	require.Len(t, summary, 2)
+	require.NotContains(t, []string{summary[0].Text, summary[1].Text}, "It rained.")
+
+	require.Len(t, Summarize(text, 10), 4)
+	require.Empty(t, Summarize(text, 0))
+	require.Empty(t, Summarize("", 3))
+}
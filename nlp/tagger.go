@@ -0,0 +1,462 @@
+package nlp
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Universal POS tags (https://universaldependencies.org/u/pos/) used by the
+// built-in English model.
+const (
+	ADJ   = "ADJ"   // Adjective
+	ADP   = "ADP"   // Adposition (preposition)
+	ADV   = "ADV"   // Adverb
+	AUX   = "AUX"   // Auxiliary verb
+	CCONJ = "CCONJ" // Coordinating conjunction
+	DET   = "DET"   // Determiner
+	INTJ  = "INTJ"  // Interjection
+	NOUN  = "NOUN"  // Noun
+	NUM   = "NUM"   // Numeral
+	PART  = "PART"  // Particle
+	PRON  = "PRON"  // Pronoun
+	PROPN = "PROPN" // Proper noun
+	PUNCT = "PUNCT" // Punctuation
+	SCONJ = "SCONJ" // Subordinating conjunction
+	SYM   = "SYM"   // Symbol
+	VERB  = "VERB"  // Verb
+	X     = "X"     // Other
+)
+
+// TaggedWord is a word with its part-of-speech tag.
+type TaggedWord struct {
+	Word string `json:"word"`
+	Tag  string `json:"tag"`
+}
+
+// Tagger is an averaged perceptron part-of-speech tagger. It tags words
+// from features of the word (suffix, shape ...) and of its neighbours,
+// including the tags it gave to the previous words. Frequent words that
+// always had the same tag in training are tagged from a dictionary.
+//
+// A trained Tagger is safe for concurrent use.
+type Tagger struct {
+	tags    []string                      // Sorted, so ties are deterministic
+	tagDict map[string]string             // Normalized word -> tag
+	weights map[string]map[string]float64 // Feature -> tag -> weight
+}
+
+// Tag dictionary thresholds: words seen at least tagDictMinCount times, with
+// the same tag at least tagDictMinRatio of the time.
+const (
+	tagDictMinCount = 5
+	tagDictMinRatio = 0.97
+)
+
+// NewTagger returns an untrained tagger.
+func NewTagger() *Tagger {
+	return &Tagger{
+		tagDict: make(map[string]string),
+		weights: make(map[string]map[string]float64),
+	}
+}
+
+// ReadCoNLL reads tagged sentences in CoNLL style: one "word<TAB>tag" per
+// line, sentences separated by blank lines and # starting comments. In the
+// 10 columns CoNLL-U format, the tag is the UPOS column and multiword token
+// lines are skipped.
+func ReadCoNLL(r io.Reader) ([][]TaggedWord, error) {
+	var (
+		sentences [][]TaggedWord
+		sentence  []TaggedWord
+	)
+	s := bufio.NewScanner(r)
+	for lnum := 1; s.Scan(); lnum++ {
+		line := strings.TrimSpace(s.Text())
+		switch {
+		case strings.HasPrefix(line, "#"):
+			continue
+		case line == "":
+			if len(sentence) > 0 {
+				sentences = append(sentences, sentence)
+				sentence = nil
+			}
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		var word TaggedWord
+		switch len(fields) {
+		case 2:
+			word = TaggedWord{Word: fields[0], Tag: fields[1]}
+		case 10:
+			if strings.ContainsAny(fields[0], "-.") {
+				continue // Multiword token or empty node
+			}
+			word = TaggedWord{Word: fields[1], Tag: fields[3]}
+		default:
+			return nil, fmt.Errorf("%d: expected 2 or 10 fields, got %d", lnum, len(fields))
+		}
+		sentence = append(sentence, word)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(sentence) > 0 {
+		sentences = append(sentences, sentence)
+	}
+	return sentences, nil
+}
+
+// Train trains t on tagged sentences, in iterations passes over them in
+// random (but reproducible) order, replacing what t learned before.
+func (t *Tagger) Train(sentences [][]TaggedWord, iterations int) {
+	t.buildTagDict(sentences)
+	t.weights = make(map[string]map[string]float64)
+
+	p := perceptron{
+		weights: t.weights,
+		totals:  make(map[featureTag]float64),
+		stamps:  make(map[featureTag]int),
+	}
+	rnd := rand.New(rand.NewPCG(1, 2))
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+
+	for range iterations {
+		rnd.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+		for _, i := range order {
+			words := make([]string, len(sentences[i]))
+			for j, w := range sentences[i] {
+				words[j] = w.Word
+			}
+
+			ctx := newTagContext(words)
+			prev, prev2 := "-START-", "-START2-"
+			for j, w := range sentences[i] {
+				guess, ok := t.tagDict[ctx.words[j+2]]
+				if !ok {
+					features := ctx.features(j, prev, prev2)
+					guess = t.predict(features)
+					p.update(w.Tag, guess, features)
+				}
+				prev2, prev = prev, guess
+			}
+		}
+	}
+	p.average()
+}
+
+// buildTagDict collects the tags and the words with an unambiguous tag.
+func (t *Tagger) buildTagDict(sentences [][]TaggedWord) {
+	counts := make(map[string]map[string]int) // Word -> tag -> count
+	tags := make(map[string]bool)
+	for _, sentence := range sentences {
+		for _, w := range sentence {
+			word := normalizeTagWord(w.Word)
+			if counts[word] == nil {
+				counts[word] = make(map[string]int)
+			}
+			counts[word][w.Tag]++
+			tags[w.Tag] = true
+		}
+	}
+
+	t.tags = t.tags[:0]
+	for tag := range tags {
+		t.tags = append(t.tags, tag)
+	}
+	sort.Strings(t.tags)
+
+	t.tagDict = make(map[string]string)
+	for word, tagCounts := range counts {
+		total, best, bestCount := 0, "", 0
+		for tag, n := range tagCounts {
+			total += n
+			if n > bestCount || (n == bestCount && tag < best) {
+				best, bestCount = tag, n
+			}
+		}
+		if total >= tagDictMinCount && float64(bestCount)/float64(total) >= tagDictMinRatio {
+			t.tagDict[word] = best
+		}
+	}
+}
+
+// Tag returns the tags of the words of a sentence.
+func (t *Tagger) Tag(words []string) []string {
+	ctx := newTagContext(words)
+	tags := make([]string, len(words))
+	prev, prev2 := "-START-", "-START2-"
+	for i := range words {
+		tag, ok := t.tagDict[ctx.words[i+2]]
+		if !ok {
+			tag = t.predict(ctx.features(i, prev, prev2))
+		}
+		tags[i] = tag
+		prev2, prev = prev, tag
+	}
+	return tags
+}
+
+// predict returns the tag with the highest score for features.
+func (t *Tagger) predict(features []string) string {
+	scores := make(map[string]float64)
+	for _, f := range features {
+		for tag, w := range t.weights[f] {
+			scores[tag] += w
+		}
+	}
+
+	best, bestScore := "", math.Inf(-1)
+	for _, tag := range t.tags {
+		if scores[tag] > bestScore {
+			best, bestScore = tag, scores[tag]
+		}
+	}
+	return best
+}
+
+// tagContext is a sentence prepared for feature extraction: words are
+// normalized and padded with two markers on each side.
+type tagContext struct {
+	words  []string // Normalized
+	shapes []string
+}
+
+func newTagContext(words []string) tagContext {
+	ctx := tagContext{
+		words:  make([]string, 0, len(words)+4),
+		shapes: make([]string, 0, len(words)+4),
+	}
+	ctx.words = append(ctx.words, "-START-", "-START2-")
+	ctx.shapes = append(ctx.shapes, "", "")
+	for _, w := range words {
+		ctx.words = append(ctx.words, normalizeTagWord(w))
+		ctx.shapes = append(ctx.shapes, wordShape(w))
+	}
+	ctx.words = append(ctx.words, "-END-", "-END2-")
+	ctx.shapes = append(ctx.shapes, "", "")
+	return ctx
+}
+
+// features returns the features of word i, following the previous tags
+// prev and prev2.
+func (ctx tagContext) features(i int, prev, prev2 string) []string {
+	i += 2 // Padding
+	word := ctx.words[i]
+	first := ""
+	if i == 2 {
+		first = " first"
+	}
+	return []string{
+		"bias",
+		"i suffix " + suffix(word, 3),
+		"i suffix2 " + suffix(word, 2),
+		"i pref1 " + prefix(word, 1),
+		"i shape " + ctx.shapes[i] + first,
+		"i-1 tag " + prev,
+		"i-2 tag " + prev2,
+		"i tag+i-2 tag " + prev + " " + prev2,
+		"i word " + word,
+		"i-1 tag+i word " + prev + " " + word,
+		"i-1 word " + ctx.words[i-1],
+		"i-1 suffix " + suffix(ctx.words[i-1], 3),
+		"i-2 word " + ctx.words[i-2],
+		"i+1 word " + ctx.words[i+1],
+		"i+1 suffix " + suffix(ctx.words[i+1], 3),
+		"i+1 shape " + ctx.shapes[i+1],
+		"i+2 word " + ctx.words[i+2],
+	}
+}
+
+// normalizeTagWord folds the case of w and maps numbers to a single word,
+// so they share their features.
+func normalizeTagWord(w string) string {
+	switch {
+	case strings.ContainsFunc(w, unicode.IsDigit) && strings.IndexFunc(w, unicode.IsLetter) < 0:
+		if len(w) == 4 {
+			return "!YEAR"
+		}
+		return "!DIGITS"
+	case strings.Contains(w, "-") && w[0] != '-':
+		return "!HYPHEN"
+	}
+	return Fold(w)
+}
+
+// wordShape returns the case of w: "Xx" (capitalized), "X" (upper case),
+// "x" (lower case) or "" (other).
+func wordShape(w string) string {
+	r, _ := utf8.DecodeRuneInString(w)
+	switch {
+	case strings.ToUpper(w) == w && strings.ToLower(w) != w:
+		return "X"
+	case unicode.IsUpper(r):
+		return "Xx"
+	case unicode.IsLower(r):
+		return "x"
+	}
+	return ""
+}
+
+func suffix(w string, n int) string {
+	runes := []rune(w)
+	return string(runes[max(len(runes)-n, 0):])
+}
+
+func prefix(w string, n int) string {
+	runes := []rune(w)
+	return string(runes[:min(n, len(runes))])
+}
+
+type featureTag struct {
+	feature, tag string
+}
+
+// perceptron trains weights, keeping the totals of each weight over time
+// so the final weights are their average, which generalizes better than
+// the last ones.
+type perceptron struct {
+	weights map[string]map[string]float64
+	totals  map[featureTag]float64 // Sum of the weight over updates up to stamps
+	stamps  map[featureTag]int     // Update of the last change of the weight
+	n       int                    // Number of updates
+}
+
+func (p *perceptron) update(truth, guess string, features []string) {
+	p.n++
+	if truth == guess {
+		return
+	}
+	for _, f := range features {
+		p.add(f, truth, 1)
+		p.add(f, guess, -1)
+	}
+}
+
+func (p *perceptron) add(feature, tag string, v float64) {
+	if p.weights[feature] == nil {
+		p.weights[feature] = make(map[string]float64)
+	}
+	key := featureTag{feature, tag}
+	w := p.weights[feature][tag]
+	p.totals[key] += float64(p.n-p.stamps[key]) * w
+	p.stamps[key] = p.n
+	p.weights[feature][tag] = w + v
+}
+
+// average replaces the weights by their average, rounded to 3 decimals to
+// keep saved models small. Null weights are dropped.
+func (p *perceptron) average() {
+	for feature, weights := range p.weights {
+		for tag, w := range weights {
+			key := featureTag{feature, tag}
+			total := p.totals[key] + float64(p.n-p.stamps[key])*w
+			avg := math.Round(total/float64(p.n)*1000) / 1000
+			if avg == 0 {
+				delete(weights, tag)
+				continue
+			}
+			weights[tag] = avg
+		}
+		if len(weights) == 0 {
+			delete(p.weights, feature)
+		}
+	}
+}
+
+// taggerJSON is the serialized form of a Tagger.
+type taggerJSON struct {
+	Tags    []string                      `json:"tags"`
+	TagDict map[string]string             `json:"tag_dict"`
+	Weights map[string]map[string]float64 `json:"weights"`
+}
+
+// Save writes the model of t as JSON.
+func (t *Tagger) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(taggerJSON{
+		Tags:    t.tags,
+		TagDict: t.tagDict,
+		Weights: t.weights,
+	})
+}
+
+// LoadTagger reads a model written by Save.
+func LoadTagger(r io.Reader) (*Tagger, error) {
+	var data taggerJSON
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	if len(data.Tags) == 0 {
+		return nil, fmt.Errorf("bad tagger model: no tags")
+	}
+
+	t := NewTagger()
+	t.tags = data.Tags
+	sort.Strings(t.tags)
+	if data.TagDict != nil {
+		t.tagDict = data.TagDict
+	}
+	if data.Weights != nil {
+		t.weights = data.Weights
+	}
+	return t, nil
+}
+
+// English model, trained on testdata/pos_train.conll (see TestTaggerModel)
+//
+//go:embed pos/en.json
+var taggerModel string
+
+var defaultTagger = sync.OnceValue(func() *Tagger {
+	t, err := LoadTagger(strings.NewReader(taggerModel))
+	if err != nil {
+		panic(fmt.Sprintf("nlp: bad embedded tagger model - %s", err))
+	}
+	return t
+})
+
+// Tag sets the POS field of tokens to their part of speech, using the
+// built-in English model on their Text, and returns them. Tag the tokens of
+// a sentence, before stop words are removed, since the tags depend on the
+// neighbouring words.
+func Tag(tokens []Token) []Token {
+	words := make([]string, len(tokens))
+	for i, tok := range tokens {
+		words[i] = tok.Text
+	}
+	for i, tag := range defaultTagger().Tag(words) {
+		tokens[i].POS = tag
+	}
+	return tokens
+}
+
+// TagFilter sets the part of speech of tokens, see Tag. Use it before
+// filters that drop tokens.
+func TagFilter() TokenFilter {
+	return FilterFunc(Tag)
+}
+
+// KeepPOS keeps only the tokens with one of tags as part of speech, e.g.
+// KeepPOS(NOUN, PROPN) for keywords. It must follow TagFilter.
+func KeepPOS(tags ...string) TokenFilter {
+	keepTags := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		keepTags[tag] = true
+	}
+	return keep(func(tok Token) bool {
+		return keepTags[tok.POS]
+	})
+}
@@ -0,0 +1,124 @@
+package nlp
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var updateTagger = flag.Bool("update-tagger", false, "retrain pos/en.json from testdata/pos_train.conll")
+
+// taggerIterations is the number of training passes of the built-in model.
+const taggerIterations = 10
+
+func readCoNLLFile(t *testing.T, path string) [][]TaggedWord {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	sentences, err := ReadCoNLL(file)
+	require.NoError(t, err)
+	return sentences
+}
+
+// accuracy returns the fraction of words of sentences tagged right by tagger.
+func accuracy(tagger *Tagger, sentences [][]TaggedWord) float64 {
+	total, ok := 0, 0
+	for _, sentence := range sentences {
+		words := make([]string, len(sentence))
+		for i, w := range sentence {
+			words[i] = w.Word
+		}
+		for i, tag := range tagger.Tag(words) {
+			total++
+			if tag == sentence[i].Tag {
+				ok++
+			}
+		}
+	}
+	return float64(ok) / float64(total)
+}
+
+// TestTaggerModel checks that the built-in model is the one trained from
+// testdata, run with -update-tagger to retrain it.
+func TestTaggerModel(t *testing.T) {
+	tagger := NewTagger()
+	tagger.Train(readCoNLLFile(t, "testdata/pos_train.conll"), taggerIterations)
+	var buf bytes.Buffer
+	require.NoError(t, tagger.Save(&buf))
+
+	if *updateTagger {
+		require.NoError(t, os.WriteFile("pos/en.json", buf.Bytes(), 0o644))
+		return
+	}
+	require.Equal(t, taggerModel, buf.String(), "stale model, run go test -run TestTaggerModel -update-tagger")
+}
+
+func TestTaggerAccuracy(t *testing.T) {
+	train := readCoNLLFile(t, "testdata/pos_train.conll")
+	test := readCoNLLFile(t, "testdata/pos_test.conll")
+	require.Greater(t, accuracy(defaultTagger(), train), 0.98)
+	acc := accuracy(defaultTagger(), test)
+	t.Logf("accuracy: %.3f", acc)
+	require.Greater(t, acc, 0.9)
+}
+
+func TestTaggerSaveLoad(t *testing.T) {
+	tagger := NewTagger()
+	tagger.Train(readCoNLLFile(t, "testdata/pos_train.conll"), 2)
+
+	var buf bytes.Buffer
+	require.NoError(t, tagger.Save(&buf))
+	loaded, err := LoadTagger(&buf)
+	require.NoError(t, err)
+
+	words := strings.Fields("The detective found a small key under the door")
+	require.Equal(t, tagger.Tag(words), loaded.Tag(words))
+
+	_, err = LoadTagger(strings.NewReader("{}"))
+	require.Error(t, err)
+	_, err = LoadTagger(strings.NewReader("{"))
+	require.Error(t, err)
+}
+
+func TestReadCoNLL(t *testing.T) {
+	conll := "# A comment\nThe\tDET\ncat\tNOUN\n\n\nsat\tVERB\n"
+	sentences, err := ReadCoNLL(strings.NewReader(conll))
+	require.NoError(t, err)
+	require.Equal(t, [][]TaggedWord{
+		{{"The", DET}, {"cat", NOUN}},
+		{{"sat", VERB}},
+	}, sentences)
+
+	conllu := "# text = Don't go\n" +
+		"1-2\tDon't\t_\t_\t_\t_\t_\t_\t_\t_\n" +
+		"1\tDo\tdo\tAUX\t_\t_\t3\taux\t_\t_\n" +
+		"2\tn't\tnot\tPART\t_\t_\t3\tadvmod\t_\t_\n" +
+		"3\tgo\tgo\tVERB\t_\t_\t0\troot\t_\t_\n"
+	sentences, err = ReadCoNLL(strings.NewReader(conllu))
+	require.NoError(t, err)
+	require.Equal(t, [][]TaggedWord{{{"Do", AUX}, {"n't", PART}, {"go", VERB}}}, sentences)
+
+	_, err = ReadCoNLL(strings.NewReader("The DET NOUN\n"))
+	require.Error(t, err)
+}
+
+func TestTag(t *testing.T) {
+	tokens := Tag(SplitWords("The old man walked slowly into the dark room"))
+	var tags []string
+	for _, tok := range tokens {
+		tags = append(tags, tok.POS)
+	}
+	require.Equal(t, []string{DET, ADJ, NOUN, VERB, ADV, ADP, DET, ADJ, NOUN}, tags)
+
+	require.Empty(t, Tag(nil))
+}
+
+func TestKeepPOS(t *testing.T) {
+	a := NewAnalyzer(TagFilter(), Lowercase(), KeepPOS(NOUN, PROPN), LemmaFilter())
+	require.Equal(t, []string{"holmes", "pipe", "pocket"}, a.Terms("Holmes took a pipe from his pocket"))
+}
@@ -0,0 +1,237 @@
+package nlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// Vector is a sparse document vector, term index -> weight.
+type Vector map[int]float64
+
+// Norm returns the Euclidean norm of v.
+func (v Vector) Norm() float64 {
+	sum := 0.0
+	for _, i := range v.indices() {
+		sum += v[i] * v[i]
+	}
+	return math.Sqrt(sum)
+}
+
+// indices returns the term indices of v, sorted: floating point addition
+// isn't associative, so sums in map order could differ between runs.
+func (v Vector) indices() []int {
+	indices := make([]int, 0, len(v))
+	for i := range v {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices
+}
+
+// Cosine returns the cosine similarity of a and b, 0 if one of them is empty.
+func Cosine(a, b Vector) float64 {
+	if len(b) < len(a) {
+		a, b = b, a // Iterate over the smaller one
+	}
+	dot := 0.0
+	for _, i := range a.indices() {
+		dot += a[i] * b[i]
+	}
+	if dot == 0 {
+		return 0
+	}
+	return dot / (a.Norm() * b.Norm())
+}
+
+// Keyword is a term of a document with its score.
+type Keyword struct {
+	Term  string  `json:"term"`
+	Word  string  `json:"word"` // Most frequent normalized form of Term in the document
+	Score float64 `json:"score"`
+}
+
+// TFIDF is a TF-IDF model: the vocabulary of a corpus, with the inverse
+// document frequency (IDF) of each term. Documents are tokenized with
+// Tokenize. A fitted model is safe for concurrent use.
+type TFIDF struct {
+	opts    []Option
+	terms   []string       // index -> term
+	index   map[string]int // term -> index
+	df      []int          // Number of documents with the term
+	idf     []float64
+	numDocs int
+}
+
+// NewTFIDF returns an empty TF-IDF model, opts are passed to Tokenize.
+func NewTFIDF(opts ...Option) *TFIDF {
+	return &TFIDF{opts: opts, index: make(map[string]int)}
+}
+
+// Fit learns the vocabulary and IDF table from docs, replacing the current
+// ones.
+func (m *TFIDF) Fit(docs []string) {
+	m.terms, m.df, m.index = nil, nil, make(map[string]int)
+	for _, doc := range docs {
+		seen := make(map[string]bool)
+		for _, term := range Tokenize(doc, m.opts...) {
+			if seen[term] {
+				continue
+			}
+			seen[term] = true
+
+			i, ok := m.index[term]
+			if !ok {
+				i = len(m.terms)
+				m.index[term] = i
+				m.terms = append(m.terms, term)
+				m.df = append(m.df, 0)
+			}
+			m.df[i]++
+		}
+	}
+	m.numDocs = len(docs)
+	m.computeIDF()
+}
+
+// computeIDF computes the smoothed IDF, ln((1+N)/(1+df)) + 1, which is
+// positive so terms in every document still count.
+func (m *TFIDF) computeIDF() {
+	m.idf = make([]float64, len(m.df))
+	for i, df := range m.df {
+		m.idf[i] = math.Log(float64(1+m.numDocs)/float64(1+df)) + 1
+	}
+}
+
+// Len returns the vocabulary size.
+func (m *TFIDF) Len() int {
+	return len(m.terms)
+}
+
+// Term returns the term at index i of the vocabulary.
+func (m *TFIDF) Term(i int) string {
+	return m.terms[i]
+}
+
+// IDF returns the inverse document frequency of term, 0 for unknown terms.
+func (m *TFIDF) IDF(term string) float64 {
+	i, ok := m.index[term]
+	if !ok {
+		return 0
+	}
+	return m.idf[i]
+}
+
+// termCounts returns the counts of the vocabulary terms of doc, and the most
+// frequent normalized form of each term.
+func (m *TFIDF) termCounts(doc string) (map[int]int, map[int]string) {
+	counts := make(map[int]int)
+	forms := make(map[int]map[string]int)
+	for _, tok := range TokenizeDetailed(doc, m.opts...) {
+		i, ok := m.index[tok.Stem]
+		if !ok {
+			continue
+		}
+		counts[i]++
+		if forms[i] == nil {
+			forms[i] = make(map[string]int)
+		}
+		forms[i][tok.Normalized]++
+	}
+
+	words := make(map[int]string, len(forms))
+	for i, fs := range forms {
+		best := ""
+		for form, n := range fs {
+			if n > fs[best] || (n == fs[best] && form < best) {
+				best = form
+			}
+		}
+		words[i] = best
+	}
+	return counts, words
+}
+
+// Transform returns the TF-IDF vector of doc, normalized to unit length.
+// Terms not in the vocabulary are ignored.
+func (m *TFIDF) Transform(doc string) Vector {
+	counts, _ := m.termCounts(doc)
+	v := make(Vector, len(counts))
+	for i, n := range counts {
+		v[i] = float64(n) * m.idf[i]
+	}
+	if norm := v.Norm(); norm > 0 {
+		for i := range v {
+			v[i] /= norm
+		}
+	}
+	return v
+}
+
+// Similarity returns the cosine similarity of the TF-IDF vectors of a and b.
+func (m *TFIDF) Similarity(a, b string) float64 {
+	return Cosine(m.Transform(a), m.Transform(b))
+}
+
+// Keywords returns the n terms of doc with the highest TF-IDF weight, all
+// of them if n is negative.
+func (m *TFIDF) Keywords(doc string, n int) []Keyword {
+	counts, words := m.termCounts(doc)
+	keywords := make([]Keyword, 0, len(counts))
+	for i, c := range counts {
+		keywords = append(keywords, Keyword{
+			Term:  m.terms[i],
+			Word:  words[i],
+			Score: float64(c) * m.idf[i],
+		})
+	}
+
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].Score != keywords[j].Score {
+			return keywords[i].Score > keywords[j].Score
+		}
+		return keywords[i].Term < keywords[j].Term
+	})
+	if n >= 0 && len(keywords) > n {
+		keywords = keywords[:n]
+	}
+	return keywords
+}
+
+// tfidfJSON is the serialized form of a TFIDF model.
+type tfidfJSON struct {
+	NumDocs int      `json:"num_docs"`
+	Terms   []string `json:"terms"`
+	DF      []int    `json:"df"`
+}
+
+// Save writes the vocabulary and document frequencies of m as JSON.
+func (m *TFIDF) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(tfidfJSON{
+		NumDocs: m.numDocs,
+		Terms:   m.terms,
+		DF:      m.df,
+	})
+}
+
+// LoadTFIDF reads a model written by Save. The options are not saved, use
+// the ones of the saved model.
+func LoadTFIDF(r io.Reader, opts ...Option) (*TFIDF, error) {
+	var data tfidfJSON
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return nil, err
+	}
+	if len(data.Terms) != len(data.DF) {
+		return nil, fmt.Errorf("bad TF-IDF model: %d terms, %d document frequencies", len(data.Terms), len(data.DF))
+	}
+
+	m := NewTFIDF(opts...)
+	m.numDocs, m.terms, m.df = data.NumDocs, data.Terms, data.DF
+	for i, term := range m.terms {
+		m.index[term] = i
+	}
+	m.computeIDF()
+	return m, nil
+}
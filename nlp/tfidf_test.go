@@ -0,0 +1,85 @@
+package nlp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var tfidfDocs = []string{
+	"Sherlock Holmes lived in Baker Street with Doctor Watson.",
+	"Doctor Watson wrote about the cases of Sherlock Holmes.",
+	"The red-headed league met in a London office.",
+	"Baker Street is a street in London.",
+}
+
+func TestTFIDF(t *testing.T) {
+	m := NewTFIDF()
+	m.Fit(tfidfDocs)
+
+	require.Greater(t, m.Len(), 10)
+	// Rare terms weigh more than common ones
+	require.Greater(t, m.IDF("leagu"), m.IDF("holm"))
+	require.Equal(t, 0.0, m.IDF("moriarty"))
+
+	v := m.Transform(tfidfDocs[0])
+	require.InDelta(t, 1.0, v.Norm(), 1e-9)
+	require.Empty(t, m.Transform("Moriarty!"))
+
+	// Documents 0 and 1 share Holmes and Watson, 0 and 2 share nothing but "the"
+	sim01 := m.Similarity(tfidfDocs[0], tfidfDocs[1])
+	sim02 := m.Similarity(tfidfDocs[0], tfidfDocs[2])
+	require.Greater(t, sim01, sim02)
+	require.InDelta(t, 1.0, m.Similarity(tfidfDocs[3], tfidfDocs[3]), 1e-9)
+	require.Equal(t, 0.0, Cosine(v, Vector{}))
+}
+
+func TestTFIDFKeywords(t *testing.T) {
+	m := NewTFIDF()
+	m.Fit(tfidfDocs)
+
+	keywords := m.Keywords("Baker Street is a street in London.", 2)
+	require.Len(t, keywords, 2)
+	require.Equal(t, Keyword{Term: "street", Word: "street", Score: 2 * m.IDF("street")}, keywords[0])
+
+	keywords = m.Keywords(tfidfDocs[2], 100)
+	for _, k := range keywords {
+		require.Greater(t, k.Score, 0.0)
+	}
+	require.Equal(t, "league", keywordWord(keywords, "leagu"))
+
+	require.Equal(t, keywords, m.Keywords(tfidfDocs[2], -1))
+	require.Empty(t, m.Keywords(tfidfDocs[2], 0))
+}
+
+func keywordWord(keywords []Keyword, term string) string {
+	for _, k := range keywords {
+		if k.Term == term {
+			return k.Word
+		}
+	}
+	return ""
+}
+
+func TestTFIDFSaveLoad(t *testing.T) {
+	m := NewTFIDF(WithAnalyzer(NewAnalyzer(Lowercase())))
+	m.Fit(tfidfDocs)
+
+	var buf bytes.Buffer
+	require.NoError(t, m.Save(&buf))
+
+	loaded, err := LoadTFIDF(&buf, WithAnalyzer(NewAnalyzer(Lowercase())))
+	require.NoError(t, err)
+	require.Equal(t, m.Len(), loaded.Len())
+	// Exactly equal, weights are summed in a deterministic order
+	for _, doc := range tfidfDocs {
+		require.Equal(t, m.Transform(doc), loaded.Transform(doc))
+	}
+
+	_, err = LoadTFIDF(strings.NewReader(`{"terms": ["a"], "df": []}`))
+	require.Error(t, err)
+	_, err = LoadTFIDF(strings.NewReader(`{`))
+	require.Error(t, err)
+}
@@ -0,0 +1,27 @@
+package nlp
+
+// Token is a word of the source text together with its analysis.
+type Token struct {
+	Text       string `json:"text"`       // As it appears in the source text
+	Normalized string `json:"normalized"` // Case folded Text
+	Stem       string `json:"stem"`       // Term after the filters, Normalized after stemming by default
+
+	// Offsets of Text in the source, source[Start:End] == Text
+	Start     int `json:"start"`
+	End       int `json:"end"`
+	RuneStart int `json:"rune_start"`
+	RuneEnd   int `json:"rune_end"`
+
+	// Index of the word in the text, starting at 0. Words dropped by filters
+	// leave gaps, synonyms share the position of their word.
+	Position int `json:"position"`
+
+	// Part of speech, a Universal POS tag such as "NOUN", set by Tag
+	POS string `json:"pos,omitempty"`
+}
+
+// TokenizeDetailed is like Tokenize but returns the tokens with their source
+// text and offsets, so they can be highlighted or mapped back to the text.
+func TokenizeDetailed(text string, opts ...Option) []Token {
+	return newOptions(opts).newAnalyzer().Analyze(text)
+}
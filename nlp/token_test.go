@@ -0,0 +1,28 @@
+package nlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeDetailed(t *testing.T) {
+	text := "Les élèves, les ÉLÈVES"
+	tokens := TokenizeDetailed(text, WithLanguage("fr"))
+
+	expected := []Token{
+		{Text: "Les", Normalized: "les", Stem: "le", Start: 0, End: 3, RuneStart: 0, RuneEnd: 3, Position: 0},
+		{Text: "élèves", Normalized: "élèves", Stem: "élev", Start: 4, End: 12, RuneStart: 4, RuneEnd: 10, Position: 1},
+		{Text: "les", Normalized: "les", Stem: "le", Start: 14, End: 17, RuneStart: 12, RuneEnd: 15, Position: 2},
+		{Text: "ÉLÈVES", Normalized: "élèves", Stem: "élev", Start: 18, End: 26, RuneStart: 16, RuneEnd: 22, Position: 3},
+	}
+	require.Equal(t, expected, tokens)
+
+	runes := []rune(text)
+	for _, tok := range tokens {
+		require.Equal(t, tok.Text, text[tok.Start:tok.End])
+		require.Equal(t, tok.Text, string(runes[tok.RuneStart:tok.RuneEnd]))
+	}
+
+	require.Nil(t, TokenizeDetailed("?!"))
+}